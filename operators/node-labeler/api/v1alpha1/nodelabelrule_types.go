@@ -0,0 +1,103 @@
+/*
+Copyright 2026 Node Labeler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HostnameExpression matches a node's hostname. Exactly one field is set.
+// +kubebuilder:validation:XValidation:rule="[has(self.substring), has(self.glob), has(self.regex)].filter(x, x).size() == 1",message="exactly one of substring, glob and regex must be set"
+type HostnameExpression struct {
+	// Substring matches hostnames containing the value, e.g. "-compute-".
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Substring *string `json:"substring,omitempty"`
+
+	// Glob matches the whole hostname against a shell pattern, e.g.
+	// "rke2-prod-compute-*". "*" and "?" do not match "/".
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Glob *string `json:"glob,omitempty"`
+
+	// Regex matches hostnames against an RE2 regular expression. It is not
+	// anchored; use ^ and $ to match the whole hostname.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Regex *string `json:"regex,omitempty"`
+}
+
+// NodeLabelRuleSpec defines which nodes a rule selects and what it applies.
+// +kubebuilder:validation:XValidation:rule="has(self.labels) || has(self.taints) || has(self.annotations)",message="at least one of labels, taints and annotations must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.taints) || self.taints.all(t, t.effect in ['NoSchedule', 'PreferNoSchedule', 'NoExecute'])",message="taint effect must be NoSchedule, PreferNoSchedule or NoExecute"
+type NodeLabelRuleSpec struct {
+	// Hostnames selects the nodes whose name matches any of the expressions.
+	// +kubebuilder:validation:MinItems=1
+	Hostnames []HostnameExpression `json:"hostnames"`
+
+	// Labels are applied to every matching node.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Taints are added to every matching node. A taint is identified by its
+	// key and effect; one already on the node is left as it is.
+	// +optional
+	Taints []corev1.Taint `json:"taints,omitempty"`
+
+	// Annotations are applied to every matching node, e.g. hints for the
+	// cluster autoscaler.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Priority decides which rule sets a label, annotation or taint when
+	// several matching rules set it to different values; the higher
+	// priority wins. At equal priority, a rule whose glob or anchored regex
+	// matches the whole hostname wins over a substring or unanchored regex.
+	// Rules still tied are a conflict: the value is not applied and the node
+	// gets a LabelConflict event.
+	// +kubebuilder:default=0
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=nlr
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NodeLabelRule labels, taints and annotates the nodes whose hostname matches
+// its expressions.
+type NodeLabelRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NodeLabelRuleSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// NodeLabelRuleList contains a list of NodeLabelRule.
+type NodeLabelRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeLabelRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodeLabelRule{}, &NodeLabelRuleList{})
+}
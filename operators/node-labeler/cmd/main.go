@@ -19,17 +19,22 @@ package main
 import (
 	"flag"
 	"os"
+	"strings"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
+	labelingv1alpha1 "github.com/node-labeler/node-labeler/api/v1alpha1"
+	"github.com/node-labeler/node-labeler/internal/capi"
 	"github.com/node-labeler/node-labeler/internal/controller"
 	_ "github.com/node-labeler/node-labeler/internal/metrics"
 )
@@ -41,18 +46,38 @@ var (
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(labelingv1alpha1.AddToScheme(scheme))
 }
 
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var capiLabelPrefixes string
+	var capiKubeconfig string
+	var capiNamespace string
+	var enforcementMode string
+	var dryRun bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080",
 		"The address the metrics endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081",
 		"The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager.")
+	flag.StringVar(&enforcementMode, "enforcement-mode", string(controller.ModeAddMissing),
+		"What to change on nodes: observe (log only), add-missing (add, never overwrite) "+
+			"or enforce (also correct labels and annotations that disagree with the rules).")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"Work out the changes for --enforcement-mode without making them. "+
+			"GET /debug/nodes on the metrics endpoint shows what would change.")
+	flag.StringVar(&capiLabelPrefixes, "capi-label-prefixes", "",
+		"Comma-separated label key prefixes to copy from each node's Cluster API machine pool. "+
+			"Empty disables Cluster API label sourcing.")
+	flag.StringVar(&capiKubeconfig, "capi-kubeconfig", "",
+		"Kubeconfig of the cluster holding the Cluster API Machines, such as the Rancher management cluster. "+
+			"Empty uses the cluster the nodes are in.")
+	flag.StringVar(&capiNamespace, "capi-namespace", "fleet-default",
+		"Namespace of the Machines of nodes without a cluster.x-k8s.io/cluster-namespace annotation.")
 
 	opts := zap.Options{
 		Development: true,
@@ -62,6 +87,12 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	mode, err := controller.ParseEnforcementMode(enforcementMode)
+	if err != nil {
+		setupLog.Error(err, "invalid --enforcement-mode")
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
@@ -76,14 +107,43 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := (&controller.NodeReconciler{
+	var machines *capi.Resolver
+	if prefixes := splitList(capiLabelPrefixes); len(prefixes) > 0 {
+		cfg := mgr.GetConfig()
+		if capiKubeconfig != "" {
+			if cfg, err = clientcmd.BuildConfigFromFlags("", capiKubeconfig); err != nil {
+				setupLog.Error(err, "unable to load Cluster API kubeconfig", "path", capiKubeconfig)
+				os.Exit(1)
+			}
+		}
+		// Uncached: a node is only reconciled on create and label changes,
+		// which does not justify watching every Machine of the management cluster
+		capiClient, err := client.New(cfg, client.Options{})
+		if err != nil {
+			setupLog.Error(err, "unable to create Cluster API client")
+			os.Exit(1)
+		}
+		machines = &capi.Resolver{Client: capiClient, Namespace: capiNamespace, Prefixes: prefixes}
+		setupLog.Info("sourcing labels from Cluster API machine pools",
+			"prefixes", prefixes, "remote", capiKubeconfig != "", "namespace", capiNamespace)
+	}
+
+	reconciler := &controller.NodeReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorder("node-labeler"),
-	}).SetupWithManager(mgr); err != nil {
+		Machines: machines,
+		Mode:     mode,
+		DryRun:   dryRun,
+	}
+	if err := reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Node")
 		os.Exit(1)
 	}
+	if err := mgr.AddMetricsServerExtraHandler("/debug/nodes", reconciler.ReportHandler()); err != nil {
+		setupLog.Error(err, "unable to set up node report")
+		os.Exit(1)
+	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -94,9 +154,20 @@ func main() {
 		os.Exit(1)
 	}
 
-	setupLog.Info("starting manager")
+	setupLog.Info("starting manager", "enforcementMode", mode, "dryRun", dryRun)
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
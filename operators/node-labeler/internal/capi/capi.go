@@ -0,0 +1,189 @@
+/*
+Copyright 2026 Node Labeler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package capi resolves the Cluster API machine pool a node was created from
+// and reads the labels configured on it.
+package capi
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Annotations the Cluster API machine controller sets on the Nodes it
+// manages, and labels the MachineSet controller sets on Machines.
+const (
+	MachineAnnotation          = "cluster.x-k8s.io/machine"
+	ClusterNamespaceAnnotation = "cluster.x-k8s.io/cluster-namespace"
+	ClusterNameAnnotation      = "cluster.x-k8s.io/cluster-name"
+
+	ClusterNameLabel           = "cluster.x-k8s.io/cluster-name"
+	MachineDeploymentNameLabel = "cluster.x-k8s.io/deployment-name"
+	MachineSetNameLabel        = "cluster.x-k8s.io/set-name"
+)
+
+// GroupVersion is the Cluster API version Machines, MachineSets and
+// MachineDeployments are read at. They are read as unstructured objects so the
+// operator does not depend on the Cluster API module.
+var GroupVersion = schema.GroupVersion{Group: "cluster.x-k8s.io", Version: "v1beta1"}
+
+// Resolver finds the machine pool of a node. Its client usually points at the
+// Rancher management cluster, which holds the Cluster API objects of the
+// downstream cluster the nodes run in.
+type Resolver struct {
+	Client client.Reader
+	// Namespace holds the Machines of nodes that have no cluster-namespace
+	// annotation. Rancher keeps them in fleet-default.
+	Namespace string
+	// Prefixes is the allow-list of label key prefixes copied onto nodes.
+	Prefixes []string
+}
+
+// Pool is the machine pool a node was created from.
+type Pool struct {
+	// Machine is the namespace/name of the node's Machine.
+	Machine string
+	// Source is the object the labels were read from, e.g.
+	// "MachineDeployment/fleet-default/prod-database".
+	Source string
+	// Labels holds the pool's template labels allowed by the prefixes.
+	Labels map[string]string
+}
+
+// Resolve returns the machine pool of node, or nil when no Machine can be
+// found for it: the node is not managed by Cluster API, or the machine
+// controller has not linked them yet.
+//
+// The Machine is found by the cluster.x-k8s.io/machine annotation, or else
+// by provider ID. Its labels are read from the template of its
+// MachineDeployment, then its MachineSet, then from the Machine itself.
+func (r *Resolver) Resolve(ctx context.Context, node *corev1.Node) (*Pool, error) {
+	machine, err := r.machine(ctx, node)
+	if err != nil || machine == nil {
+		return nil, err
+	}
+	source, labels, err := r.poolLabels(ctx, machine)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := map[string]string{}
+	for key, value := range labels {
+		if r.allowed(key) {
+			allowed[key] = value
+		}
+	}
+	return &Pool{
+		Machine: machine.GetNamespace() + "/" + machine.GetName(),
+		Source:  source,
+		Labels:  allowed,
+	}, nil
+}
+
+func (r *Resolver) machine(ctx context.Context, node *corev1.Node) (*unstructured.Unstructured, error) {
+	namespace := cmp.Or(node.Annotations[ClusterNamespaceAnnotation], r.Namespace)
+	if name := node.Annotations[MachineAnnotation]; name != "" {
+		machine := newObject("Machine")
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, machine); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("getting Machine %s/%s: %w", namespace, name, err)
+		}
+		return machine, nil
+	}
+
+	if node.Spec.ProviderID == "" {
+		return nil, nil
+	}
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(GroupVersion.WithKind("MachineList"))
+	opts := []client.ListOption{client.InNamespace(namespace)}
+	if cluster := node.Annotations[ClusterNameAnnotation]; cluster != "" {
+		opts = append(opts, client.MatchingLabels{ClusterNameLabel: cluster})
+	}
+	if err := r.Client.List(ctx, list, opts...); err != nil {
+		return nil, fmt.Errorf("listing Machines in %s: %w", namespace, err)
+	}
+	for i := range list.Items {
+		if id, _, _ := unstructured.NestedString(list.Items[i].Object, "spec", "providerID"); id == node.Spec.ProviderID {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// poolLabels returns the template labels of the pool machine belongs to and
+// the object they were read from.
+func (r *Resolver) poolLabels(ctx context.Context, machine *unstructured.Unstructured) (string, map[string]string, error) {
+	namespace := machine.GetNamespace()
+	owners := []struct{ kind, name string }{
+		{"MachineDeployment", machine.GetLabels()[MachineDeploymentNameLabel]},
+		{"MachineSet", machine.GetLabels()[MachineSetNameLabel]},
+	}
+	if owners[1].name == "" {
+		for _, ref := range machine.GetOwnerReferences() {
+			if ref.Kind == "MachineSet" {
+				owners[1].name = ref.Name
+			}
+		}
+	}
+
+	for _, owner := range owners {
+		if owner.name == "" {
+			continue
+		}
+		obj := newObject(owner.kind)
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: owner.name}, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return "", nil, fmt.Errorf("getting %s %s/%s: %w", owner.kind, namespace, owner.name, err)
+		}
+		labels, _, err := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "labels")
+		if err != nil {
+			return "", nil, fmt.Errorf("reading %s %s/%s template labels: %w", owner.kind, namespace, owner.name, err)
+		}
+		return owner.kind + "/" + namespace + "/" + owner.name, labels, nil
+	}
+
+	// A Machine without a pool, or whose pool is being deleted
+	return "Machine/" + namespace + "/" + machine.GetName(), machine.GetLabels(), nil
+}
+
+func (r *Resolver) allowed(key string) bool {
+	for _, prefix := range r.Prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func newObject(kind string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(GroupVersion.WithKind(kind))
+	return obj
+}
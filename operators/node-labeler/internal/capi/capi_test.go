@@ -0,0 +1,139 @@
+/*
+Copyright 2026 Node Labeler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capi
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func machine(name string, labels map[string]string, providerID string) *unstructured.Unstructured {
+	obj := newObject("Machine")
+	obj.SetNamespace("fleet-default")
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	if providerID != "" {
+		_ = unstructured.SetNestedField(obj.Object, providerID, "spec", "providerID")
+	}
+	return obj
+}
+
+func pool(kind, name string, labels map[string]string) *unstructured.Unstructured {
+	obj := newObject(kind)
+	obj.SetNamespace("fleet-default")
+	obj.SetName(name)
+	_ = unstructured.SetNestedStringMap(obj.Object, labels, "spec", "template", "metadata", "labels")
+	return obj
+}
+
+func newResolver(objs ...client.Object) *Resolver {
+	return &Resolver{
+		Client:    fake.NewClientBuilder().WithObjects(objs...).Build(),
+		Namespace: "fleet-default",
+		Prefixes:  []string{"workload-type", "node.example.com/"},
+	}
+}
+
+func TestResolve_MachineDeploymentByAnnotation(t *testing.T) {
+	r := newResolver(
+		machine("prod-database-abc12", map[string]string{
+			MachineDeploymentNameLabel: "prod-database",
+			MachineSetNameLabel:        "prod-database-5d8f",
+		}, ""),
+		pool("MachineDeployment", "prod-database", map[string]string{
+			"workload-type":         "database",
+			"node.example.com/disk": "nvme",
+			"cattle.io/creator":     "norman",
+		}),
+		pool("MachineSet", "prod-database-5d8f", map[string]string{"workload-type": "stale"}),
+	)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "rke2-prod-database-xyz99-abc12",
+		Annotations: map[string]string{MachineAnnotation: "prod-database-abc12"},
+	}}
+
+	got, err := r.Resolve(context.Background(), node)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	want := &Pool{
+		Machine: "fleet-default/prod-database-abc12",
+		Source:  "MachineDeployment/fleet-default/prod-database",
+		Labels:  map[string]string{"workload-type": "database", "node.example.com/disk": "nvme"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Resolve() = %+v, want %+v", got, want)
+	}
+}
+
+func TestResolve_MachineSetByProviderID(t *testing.T) {
+	m := machine("prod-compute-def34", nil, "rke2://rke2-prod-compute-xyz99-def34")
+	m.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: GroupVersion.String(), Kind: "MachineSet", Name: "prod-compute-7c2a", UID: "1",
+	}})
+	r := newResolver(
+		machine("prod-general-aaa11", nil, "rke2://rke2-prod-general-xyz99-aaa11"),
+		m,
+		pool("MachineSet", "prod-compute-7c2a", map[string]string{"workload-type": "compute"}),
+	)
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "rke2-prod-compute-xyz99-def34"},
+		Spec:       corev1.NodeSpec{ProviderID: "rke2://rke2-prod-compute-xyz99-def34"},
+	}
+
+	got, err := r.Resolve(context.Background(), node)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got == nil || got.Source != "MachineSet/fleet-default/prod-compute-7c2a" || got.Labels["workload-type"] != "compute" {
+		t.Errorf("Resolve() = %+v, want workload-type=compute from the MachineSet", got)
+	}
+}
+
+func TestResolve_NoMachine(t *testing.T) {
+	r := newResolver(machine("prod-general-aaa11", nil, "rke2://rke2-prod-general-xyz99-aaa11"))
+
+	tests := []struct {
+		name string
+		node *corev1.Node
+	}{
+		{"no annotation or provider ID", &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "standalone"}}},
+		{"annotation for a deleted Machine", &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:        "rke2-prod-general-xyz99-gone1",
+			Annotations: map[string]string{MachineAnnotation: "prod-general-gone1"},
+		}}},
+		{"unknown provider ID", &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "rke2-prod-general-xyz99-bbb22"},
+			Spec:       corev1.NodeSpec{ProviderID: "rke2://rke2-prod-general-xyz99-bbb22"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Resolve(context.Background(), tt.node)
+			if err != nil || got != nil {
+				t.Errorf("Resolve() = %+v, %v, want nil, nil", got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2026 Node Labeler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import "fmt"
+
+// SkipAnnotation opts a node out of labeling when set to "true". The
+// controller then leaves its labels, taints and annotations alone in every
+// mode.
+const SkipAnnotation = "labeling.node-labeler.io/skip"
+
+// EnforcementMode controls what the controller changes on a node.
+type EnforcementMode string
+
+const (
+	// ModeObserve changes nothing and only logs what would change.
+	ModeObserve EnforcementMode = "observe"
+	// ModeAddMissing adds the labels, taints and annotations a node is
+	// missing and leaves existing values alone. It is the default.
+	ModeAddMissing EnforcementMode = "add-missing"
+	// ModeEnforce also corrects labels and annotations whose value disagrees
+	// with the rules. Taints are still only added: a taint with the same key
+	// and effect may be a deliberate one, such as a maintenance taint.
+	ModeEnforce EnforcementMode = "enforce"
+)
+
+// ParseEnforcementMode validates the value of the --enforcement-mode flag.
+func ParseEnforcementMode(value string) (EnforcementMode, error) {
+	switch mode := EnforcementMode(value); mode {
+	case ModeObserve, ModeAddMissing, ModeEnforce:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown enforcement mode %q, want %s, %s or %s", value, ModeObserve, ModeAddMissing, ModeEnforce)
+}
@@ -18,39 +18,53 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	labelingv1alpha1 "github.com/node-labeler/node-labeler/api/v1alpha1"
+	"github.com/node-labeler/node-labeler/internal/capi"
 	"github.com/node-labeler/node-labeler/internal/metrics"
+	"github.com/node-labeler/node-labeler/internal/rules"
 )
 
-const labelKey = "workload-type"
-
-// poolPatterns maps hostname substrings to workload-type label values.
-var poolPatterns = map[string]string{
-	"-general-":  "general",
-	"-compute-":  "compute",
-	"-database-": "database",
-}
-
-// NodeReconciler watches Node objects and applies workload-type labels
-// based on hostname patterns. This compensates for Rancher's cluster
-// autoscaler not propagating machine pool labels to new nodes.
+// NodeReconciler watches Node objects and applies the labels, taints and
+// annotations of the NodeLabelRules whose hostname expressions match them. This compensates for
+// Rancher's cluster autoscaler not propagating machine pool labels to new
+// nodes.
 type NodeReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder events.EventRecorder
+	// Machines, when set, also copies the labels of each node's Cluster API
+	// machine pool onto it. They take precedence over rule labels.
+	Machines *capi.Resolver
+	// Mode is what the controller may change on a node; empty means
+	// ModeAddMissing.
+	Mode EnforcementMode
+	// DryRun works out the changes for Mode but makes none, and emits no
+	// events. See Report for what it would do.
+	DryRun bool
 }
 
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=labeling.node-labeler.io,resources=nodelabelrules,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machinesets;machinedeployments,verbs=get;list
 // +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 
 func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -60,60 +74,239 @@ func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	if err := r.Get(ctx, req.NamespacedName, node); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
-
-	// Skip if already labeled
-	if _, ok := node.Labels[labelKey]; ok {
+	if node.Annotations[SkipAnnotation] == "true" {
+		logger.V(1).Info("skipping opted-out node", "node", node.Name)
 		return ctrl.Result{}, nil
 	}
 
-	// Match hostname to pool type
-	poolType := matchPool(node.Name)
-	if poolType == "" {
+	compiled, err := r.loadRules(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	plan, err := r.plan(ctx, node, compiled)
+	if err != nil {
+		logger.Error(err, "failed to evaluate node", "node", node.Name)
+		metrics.ErrorsTotal.Inc()
+		return ctrl.Result{}, err
+	}
+
+	if len(plan.Conflicts) > 0 {
+		// Tied rules disagree: apply neither value rather than pick one
+		conflicts := formatConflicts(plan.Conflicts)
+		logger.Info("conflicting rules, not applying", "node", node.Name, "conflicts", conflicts)
+		if !r.DryRun {
+			r.Recorder.Eventf(node, nil, corev1.EventTypeWarning, "LabelConflict", "LabelNode",
+				"Rules of equal priority and specificity disagree, not applying: %s", conflicts)
+		}
+		metrics.LabelConflictsTotal.Add(float64(len(plan.Conflicts)))
+	}
+
+	applied := formatApplied(plan.Labels, plan.Taints, plan.Annotations)
+	corrections := plan.Corrections()
+	if r.Mode == ModeObserve || r.DryRun {
+		if applied != "" || len(plan.Drift) > 0 {
+			logger.Info("would label node", "node", node.Name, "applied", applied,
+				"drift", formatDrift(plan.Drift), "corrections", len(corrections), "rules", plan.Rules, "dryRun", r.DryRun)
+		}
+		return ctrl.Result{}, nil
+	}
+	if len(plan.Drift) > len(corrections) {
+		// Existing values are left alone outside enforce mode
+		logger.V(1).Info("leaving drifted values", "node", node.Name, "drift", formatDrift(plan.Drift))
+	}
+	drift := formatDrift(corrections)
+	if applied == "" && drift == "" {
 		return ctrl.Result{}, nil
 	}
 
-	// Patch label onto node
+	// Taints are a list that a merge patch replaces whole, so lock on the
+	// resourceVersion rather than drop a taint another controller just added
 	patch := client.MergeFrom(node.DeepCopy())
+	if len(plan.Taints) > 0 {
+		patch = client.MergeFromWithOptions(node.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	}
 	if node.Labels == nil {
 		node.Labels = make(map[string]string)
 	}
-	node.Labels[labelKey] = poolType
+	maps.Copy(node.Labels, plan.Labels)
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	maps.Copy(node.Annotations, plan.Annotations)
+	for _, d := range corrections {
+		if d.Kind == "label" {
+			node.Labels[d.Key] = d.Want
+		} else {
+			node.Annotations[d.Key] = d.Want
+		}
+	}
+	node.Spec.Taints = append(node.Spec.Taints, plan.Taints...)
 
 	if err := r.Patch(ctx, node, patch); err != nil {
-		logger.Error(err, "failed to patch node label", "node", node.Name, "label", poolType)
+		if apierrors.IsConflict(err) {
+			// The node changed since it was read; retry against the new version
+			logger.V(1).Info("node changed while tainting, retrying", "node", node.Name)
+			return ctrl.Result{}, err
+		}
+		logger.Error(err, "failed to patch node", "node", node.Name, "applied", applied, "drift", drift)
 		metrics.ErrorsTotal.Inc()
 		return ctrl.Result{}, err
 	}
 
-	logger.Info("labeled node", "node", node.Name, "workload-type", poolType)
-	r.Recorder.Eventf(node, nil, corev1.EventTypeNormal, "Labeled", "LabelNode", "Applied workload-type=%s", poolType)
-	metrics.LabelsAppliedTotal.Inc()
+	if applied != "" {
+		logger.Info("labeled node", "node", node.Name, "applied", applied, "rules", plan.Rules)
+		r.Recorder.Eventf(node, nil, corev1.EventTypeNormal, "Labeled", "LabelNode", "Applied %s", applied)
+	}
+	if drift != "" {
+		logger.Info("corrected drift", "node", node.Name, "drift", drift, "rules", plan.Rules)
+		r.Recorder.Eventf(node, nil, corev1.EventTypeNormal, "DriftCorrected", "LabelNode", "Corrected %s", drift)
+	}
+	metrics.DriftCorrectionsTotal.Add(float64(len(corrections)))
+	metrics.LabelsAppliedTotal.Add(float64(len(plan.Labels)))
+	metrics.TaintsAppliedTotal.Add(float64(len(plan.Taints)))
+	metrics.AnnotationsAppliedTotal.Add(float64(len(plan.Annotations)))
 	return ctrl.Result{}, nil
 }
 
+// loadRules lists the NodeLabelRules and compiles them. Invalid rules are
+// skipped; they are reported on the rule itself when it changes.
+func (r *NodeReconciler) loadRules(ctx context.Context) ([]*rules.Rule, error) {
+	var list labelingv1alpha1.NodeLabelRuleList
+	if err := r.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("listing NodeLabelRules: %w", err)
+	}
+	compiled := make([]*rules.Rule, 0, len(list.Items))
+	for i := range list.Items {
+		rule, err := rules.Compile(&list.Items[i])
+		if err != nil {
+			log.FromContext(ctx).Error(err, "skipping invalid NodeLabelRule", "rule", list.Items[i].Name)
+			continue
+		}
+		compiled = append(compiled, rule)
+	}
+	return compiled, nil
+}
+
+// nodesForRule re-evaluates every node when a NodeLabelRule changes, and
+// reports a rule that does not compile.
+func (r *NodeReconciler) nodesForRule(ctx context.Context, obj client.Object) []reconcile.Request {
+	if rule, ok := obj.(*labelingv1alpha1.NodeLabelRule); ok {
+		if _, err := rules.Compile(rule); err != nil {
+			r.Recorder.Eventf(rule, nil, corev1.EventTypeWarning, "InvalidRule", "CompileRule", "%v", err)
+		}
+	}
+
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list nodes for NodeLabelRule", "rule", obj.GetName())
+		metrics.ErrorsTotal.Inc()
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}})
+	}
+	return requests
+}
+
 func (r *NodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Node{}).
-		WithEventFilter(predicate.Funcs{
-			CreateFunc: func(e event.CreateEvent) bool { return true },
-			UpdateFunc: func(e event.UpdateEvent) bool {
-				// Re-check on update in case label was removed
-				_, hasLabel := e.ObjectNew.GetLabels()[labelKey]
-				return !hasLabel
-			},
+		For(&corev1.Node{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc:  func(e event.CreateEvent) bool { return true },
+			UpdateFunc:  r.nodeChanged,
 			DeleteFunc:  func(e event.DeleteEvent) bool { return false },
 			GenericFunc: func(e event.GenericEvent) bool { return false },
-		}).
+		})).
+		Watches(&labelingv1alpha1.NodeLabelRule{}, handler.EnqueueRequestsFromMapFunc(r.nodesForRule)).
 		Complete(r)
 }
 
-// matchPool returns the workload-type value if the hostname matches a known
-// pool pattern, or empty string if no match.
-func matchPool(hostname string) string {
-	for pattern, poolType := range poolPatterns {
-		if strings.Contains(hostname, pattern) {
-			return poolType
+// nodeChanged filters Node updates. It re-checks a node when a label or an
+// annotation set by a rule was removed or edited, once Cluster API links the
+// node to its Machine, and when the node opts back in, but not on status
+// heartbeats.
+func (r *NodeReconciler) nodeChanged(e event.UpdateEvent) bool {
+	return !maps.Equal(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) ||
+		annotationChanged(e, capi.MachineAnnotation) || annotationChanged(e, SkipAnnotation) ||
+		providerID(e.ObjectOld) != providerID(e.ObjectNew) ||
+		r.ruleAnnotationChanged(e)
+}
+
+// ruleAnnotationChanged reports whether an annotation that any rule sets was
+// added, edited or removed. Rules are read from the informer cache.
+func (r *NodeReconciler) ruleAnnotationChanged(e event.UpdateEvent) bool {
+	old, cur := e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()
+	if maps.Equal(old, cur) {
+		return false
+	}
+	var list labelingv1alpha1.NodeLabelRuleList
+	if err := r.List(context.Background(), &list); err != nil {
+		// Reconcile rather than miss drift
+		return true
+	}
+	for _, rule := range list.Items {
+		for key := range rule.Spec.Annotations {
+			if old[key] != cur[key] {
+				return true
+			}
 		}
 	}
+	return false
+}
+
+func annotationChanged(e event.UpdateEvent, key string) bool {
+	return e.ObjectOld.GetAnnotations()[key] != e.ObjectNew.GetAnnotations()[key]
+}
+
+func providerID(obj client.Object) string {
+	if node, ok := obj.(*corev1.Node); ok {
+		return node.Spec.ProviderID
+	}
 	return ""
 }
+
+// missingTaints returns the taints of want whose key and effect are not on
+// the node yet. A taint the node already has, even with another value, is
+// not touched: it may belong to the node lifecycle controller or an admin.
+func missingTaints(have, want []corev1.Taint) []corev1.Taint {
+	present := make(map[string]bool, len(have))
+	for _, taint := range have {
+		present[rules.TaintID(taint)] = true
+	}
+	var missing []corev1.Taint
+	for _, taint := range want {
+		if !present[rules.TaintID(taint)] {
+			missing = append(missing, taint)
+		}
+	}
+	return missing
+}
+
+// formatApplied renders the changes to a node for logs and events, e.g.
+// "labels workload-type=database; taints workload-type=database:NoSchedule".
+func formatApplied(labels map[string]string, taints []corev1.Taint, annotations map[string]string) string {
+	var parts []string
+	if len(labels) > 0 {
+		parts = append(parts, "labels "+formatMap(labels))
+	}
+	if len(taints) > 0 {
+		rendered := make([]string, 0, len(taints))
+		for _, taint := range taints {
+			rendered = append(rendered, taint.ToString())
+		}
+		parts = append(parts, "taints "+strings.Join(rendered, ", "))
+	}
+	if len(annotations) > 0 {
+		parts = append(parts, "annotations "+formatMap(annotations))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// formatMap renders a map as sorted key=value pairs.
+func formatMap(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		pairs = append(pairs, key+"="+m[key])
+	}
+	return strings.Join(pairs, ", ")
+}
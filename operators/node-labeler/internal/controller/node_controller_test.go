@@ -18,18 +18,62 @@ package controller
 
 import (
 	"context"
+	"reflect"
+	"strings"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	labelingv1alpha1 "github.com/node-labeler/node-labeler/api/v1alpha1"
+	"github.com/node-labeler/node-labeler/internal/capi"
+	"github.com/node-labeler/node-labeler/internal/rules"
 )
 
-func TestMatchPool(t *testing.T) {
+const labelKey = "workload-type"
+
+// poolRules mirrors the default rules in services/node-labeler/rules.yaml.
+func poolRules() []client.Object {
+	var objs []client.Object
+	for _, pool := range []string{"general", "compute", "database"} {
+		substring := "-" + pool + "-"
+		objs = append(objs, &labelingv1alpha1.NodeLabelRule{
+			ObjectMeta: metav1.ObjectMeta{Name: pool},
+			Spec: labelingv1alpha1.NodeLabelRuleSpec{
+				Hostnames: []labelingv1alpha1.HostnameExpression{{Substring: &substring}},
+				Labels:    map[string]string{labelKey: pool},
+			},
+		})
+	}
+	return objs
+}
+
+func newScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = labelingv1alpha1.AddToScheme(scheme)
+	return scheme
+}
+
+func TestPoolRules(t *testing.T) {
+	var compiled []*rules.Rule
+	for _, obj := range poolRules() {
+		rule, err := rules.Compile(obj.(*labelingv1alpha1.NodeLabelRule))
+		if err != nil {
+			t.Fatalf("Compile() error = %v", err)
+		}
+		compiled = append(compiled, rule)
+	}
 	tests := []struct {
 		hostname string
 		want     string
@@ -43,17 +87,16 @@ func TestMatchPool(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.hostname, func(t *testing.T) {
-			got := matchPool(tt.hostname)
+			got := rules.Evaluate(compiled, tt.hostname).Labels[labelKey]
 			if got != tt.want {
-				t.Errorf("matchPool(%q) = %q, want %q", tt.hostname, got, tt.want)
+				t.Errorf("Evaluate(%q)[%s] = %q, want %q", tt.hostname, labelKey, got, tt.want)
 			}
 		})
 	}
 }
 
 func TestReconcile_LabelsUnlabeledNode(t *testing.T) {
-	scheme := runtime.NewScheme()
-	_ = corev1.AddToScheme(scheme)
+	scheme := newScheme()
 
 	node := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -62,7 +105,7 @@ func TestReconcile_LabelsUnlabeledNode(t *testing.T) {
 		},
 	}
 
-	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(poolRules(), node)...).Build()
 	recorder := events.NewFakeRecorder(10)
 	r := &NodeReconciler{
 		Client:   client,
@@ -90,8 +133,7 @@ func TestReconcile_LabelsUnlabeledNode(t *testing.T) {
 }
 
 func TestReconcile_SkipsAlreadyLabeledNode(t *testing.T) {
-	scheme := runtime.NewScheme()
-	_ = corev1.AddToScheme(scheme)
+	scheme := newScheme()
 
 	node := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -102,7 +144,7 @@ func TestReconcile_SkipsAlreadyLabeledNode(t *testing.T) {
 		},
 	}
 
-	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(poolRules(), node)...).Build()
 	recorder := events.NewFakeRecorder(10)
 	r := &NodeReconciler{
 		Client:   client,
@@ -129,8 +171,7 @@ func TestReconcile_SkipsAlreadyLabeledNode(t *testing.T) {
 }
 
 func TestReconcile_SkipsCPNode(t *testing.T) {
-	scheme := runtime.NewScheme()
-	_ = corev1.AddToScheme(scheme)
+	scheme := newScheme()
 
 	node := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -139,7 +180,7 @@ func TestReconcile_SkipsCPNode(t *testing.T) {
 		},
 	}
 
-	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(poolRules(), node)...).Build()
 	recorder := events.NewFakeRecorder(10)
 	r := &NodeReconciler{
 		Client:   client,
@@ -167,8 +208,7 @@ func TestReconcile_SkipsCPNode(t *testing.T) {
 }
 
 func TestReconcile_NodeNotFound(t *testing.T) {
-	scheme := runtime.NewScheme()
-	_ = corev1.AddToScheme(scheme)
+	scheme := newScheme()
 
 	client := fake.NewClientBuilder().WithScheme(scheme).Build()
 	recorder := events.NewFakeRecorder(10)
@@ -189,8 +229,7 @@ func TestReconcile_NodeNotFound(t *testing.T) {
 }
 
 func TestReconcile_DatabaseNode(t *testing.T) {
-	scheme := runtime.NewScheme()
-	_ = corev1.AddToScheme(scheme)
+	scheme := newScheme()
 
 	node := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -199,7 +238,7 @@ func TestReconcile_DatabaseNode(t *testing.T) {
 		},
 	}
 
-	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(poolRules(), node)...).Build()
 	recorder := events.NewFakeRecorder(10)
 	r := &NodeReconciler{
 		Client:   client,
@@ -224,3 +263,409 @@ func TestReconcile_DatabaseNode(t *testing.T) {
 		t.Errorf("Label %q = %q, want %q", labelKey, got, "database")
 	}
 }
+
+func TestReconcile_AddsMissingLabelsOnly(t *testing.T) {
+	scheme := newScheme()
+
+	zone := "-database-"
+	rule := &labelingv1alpha1.NodeLabelRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "database-storage"},
+		Spec: labelingv1alpha1.NodeLabelRuleSpec{
+			Hostnames: []labelingv1alpha1.HostnameExpression{{Substring: &zone}},
+			Labels:    map[string]string{labelKey: "db", "storage-tier": "ssd"},
+			Priority:  10,
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "rke2-prod-database-xyz99-abc11",
+			Labels: map[string]string{labelKey: "database"},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(rule, node).Build()
+	recorder := events.NewFakeRecorder(10)
+	r := &NodeReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Node{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: node.Name}, updated); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := updated.Labels[labelKey]; got != "database" {
+		t.Errorf("Label %q = %q, want the existing value %q kept", labelKey, got, "database")
+	}
+	if got := updated.Labels["storage-tier"]; got != "ssd" {
+		t.Errorf("Label storage-tier = %q, want %q", got, "ssd")
+	}
+	select {
+	case evt := <-recorder.Events:
+		if !strings.Contains(evt, "Applied labels storage-tier=ssd") {
+			t.Errorf("event = %q, want it to list only the added label", evt)
+		}
+	default:
+		t.Error("expected a Labeled event")
+	}
+}
+
+func TestNodesForRule(t *testing.T) {
+	scheme := newScheme()
+
+	bad := "("
+	rule := &labelingv1alpha1.NodeLabelRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "broken"},
+		Spec: labelingv1alpha1.NodeLabelRuleSpec{
+			Hostnames: []labelingv1alpha1.HostnameExpression{{Regex: &bad}},
+			Labels:    map[string]string{labelKey: "x"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}},
+	).Build()
+	recorder := events.NewFakeRecorder(10)
+	r := &NodeReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+
+	requests := r.nodesForRule(context.Background(), rule)
+	if len(requests) != 2 {
+		t.Errorf("got %d requests, want one per node", len(requests))
+	}
+	select {
+	case evt := <-recorder.Events:
+		if !strings.Contains(evt, "InvalidRule") {
+			t.Errorf("event = %q, want InvalidRule", evt)
+		}
+	default:
+		t.Error("expected an InvalidRule event for a rule that does not compile")
+	}
+}
+
+func TestNodeChanged(t *testing.T) {
+	scheme := newScheme()
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(databaseTaintRule()).Build()
+	r := &NodeReconciler{Client: c, Scheme: scheme}
+
+	node := func(annotations map[string]string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "rke2-prod-database-abc12", Annotations: annotations}}
+	}
+	const managed = "cluster-autoscaler.kubernetes.io/scale-down-disabled"
+	tests := []struct {
+		name     string
+		old, new map[string]string
+		want     bool
+	}{
+		{"rule annotation edited", map[string]string{managed: "true"}, map[string]string{managed: "false"}, true},
+		{"rule annotation removed", map[string]string{managed: "true"}, nil, true},
+		{"skip annotation removed", map[string]string{SkipAnnotation: "true"}, nil, true},
+		{"unrelated annotation edited", map[string]string{"heartbeat": "1"}, map[string]string{"heartbeat": "2"}, false},
+		{"nothing changed", map[string]string{managed: "true"}, map[string]string{managed: "true"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := event.UpdateEvent{ObjectOld: node(tt.old), ObjectNew: node(tt.new)}
+			if got := r.nodeChanged(e); got != tt.want {
+				t.Errorf("nodeChanged = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func databaseTaintRule() *labelingv1alpha1.NodeLabelRule {
+	substring := "-database-"
+	return &labelingv1alpha1.NodeLabelRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "database"},
+		Spec: labelingv1alpha1.NodeLabelRuleSpec{
+			Hostnames: []labelingv1alpha1.HostnameExpression{{Substring: &substring}},
+			Taints: []corev1.Taint{
+				{Key: labelKey, Value: "database", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "dedicated", Value: "database", Effect: corev1.TaintEffectNoSchedule},
+			},
+			Annotations: map[string]string{"cluster-autoscaler.kubernetes.io/scale-down-disabled": "true"},
+		},
+	}
+}
+
+func TestReconcile_MergesTaints(t *testing.T) {
+	scheme := newScheme()
+
+	notReady := corev1.Taint{Key: "node.kubernetes.io/not-ready", Effect: corev1.TaintEffectNoExecute}
+	manual := corev1.Taint{Key: "dedicated", Value: "maintenance", Effect: corev1.TaintEffectNoSchedule}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "rke2-prod-database-xyz99-abc11"},
+		Spec:       corev1.NodeSpec{Taints: []corev1.Taint{notReady, manual}},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(databaseTaintRule(), node).Build()
+	recorder := events.NewFakeRecorder(10)
+	r := &NodeReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Node{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: node.Name}, updated); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	want := []corev1.Taint{notReady, manual, {Key: labelKey, Value: "database", Effect: corev1.TaintEffectNoSchedule}}
+	if !reflect.DeepEqual(updated.Spec.Taints, want) {
+		t.Errorf("Taints = %v, want existing taints kept and only the missing one added: %v", updated.Spec.Taints, want)
+	}
+	if got := updated.Annotations["cluster-autoscaler.kubernetes.io/scale-down-disabled"]; got != "true" {
+		t.Errorf("scale-down-disabled annotation = %q, want %q", got, "true")
+	}
+	select {
+	case evt := <-recorder.Events:
+		if !strings.Contains(evt, "taints workload-type=database:NoSchedule; annotations") {
+			t.Errorf("event = %q, want it to list the added taint and annotation", evt)
+		}
+	default:
+		t.Error("expected a Labeled event")
+	}
+}
+
+func TestReconcile_TaintConflictKeepsConcurrentTaint(t *testing.T) {
+	scheme := newScheme()
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "rke2-prod-database-xyz99-abc11"}}
+	notReady := corev1.Taint{Key: "node.kubernetes.io/not-ready", Effect: corev1.TaintEffectNoExecute}
+	raced := false
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(databaseTaintRule(), node).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if err := c.Get(ctx, key, obj, opts...); err != nil {
+					return err
+				}
+				// The node lifecycle controller taints the node right after our read
+				if n, ok := obj.(*corev1.Node); ok && !raced {
+					raced = true
+					fresh := n.DeepCopy()
+					fresh.Spec.Taints = append(fresh.Spec.Taints, notReady)
+					return c.Update(ctx, fresh)
+				}
+				return nil
+			},
+		}).Build()
+	r := &NodeReconciler{Client: c, Scheme: scheme, Recorder: events.NewFakeRecorder(10)}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}}
+
+	if _, err := r.Reconcile(context.Background(), req); !apierrors.IsConflict(err) {
+		t.Fatalf("Reconcile() error = %v, want a conflict", err)
+	}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("second Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Node{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: node.Name}, updated); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(updated.Spec.Taints) != 3 || updated.Spec.Taints[0] != notReady {
+		t.Errorf("Taints = %v, want the concurrent not-ready taint kept next to the two rule taints", updated.Spec.Taints)
+	}
+}
+
+func TestReconcile_MachinePoolLabels(t *testing.T) {
+	scheme := newScheme()
+
+	machine := &unstructured.Unstructured{}
+	machine.SetGroupVersionKind(capi.GroupVersion.WithKind("Machine"))
+	machine.SetNamespace("fleet-default")
+	machine.SetName("prod-gpu-abc11")
+	machine.SetLabels(map[string]string{capi.MachineDeploymentNameLabel: "prod-gpu"})
+	deployment := &unstructured.Unstructured{}
+	deployment.SetGroupVersionKind(capi.GroupVersion.WithKind("MachineDeployment"))
+	deployment.SetNamespace("fleet-default")
+	deployment.SetName("prod-gpu")
+	_ = unstructured.SetNestedStringMap(deployment.Object, map[string]string{
+		labelKey:             "gpu",
+		"rke.cattle.io/pool": "gpu",
+	}, "spec", "template", "metadata", "labels")
+
+	// The hostname says general, but the node is in the gpu pool
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "rke2-prod-general-xyz99-abc11",
+		Annotations: map[string]string{capi.MachineAnnotation: "prod-gpu-abc11"},
+	}}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(poolRules(), node)...).Build()
+	r := &NodeReconciler{
+		Client:   c,
+		Scheme:   scheme,
+		Recorder: events.NewFakeRecorder(10),
+		Machines: &capi.Resolver{
+			Client:    fake.NewClientBuilder().WithObjects(machine, deployment).Build(),
+			Namespace: "fleet-default",
+			Prefixes:  []string{labelKey},
+		},
+	}
+
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Node{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: node.Name}, updated); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	want := map[string]string{labelKey: "gpu"}
+	if !reflect.DeepEqual(updated.Labels, want) {
+		t.Errorf("Labels = %v, want %v: the machine pool wins over the hostname rule and only allowed prefixes are copied", updated.Labels, want)
+	}
+}
+
+func TestReconcile_EnforcementModes(t *testing.T) {
+	tests := []struct {
+		mode      EnforcementMode
+		wantLabel string
+		wantEvent string
+	}{
+		{ModeObserve, "general", ""},
+		{ModeAddMissing, "general", ""},
+		{"", "general", ""},
+		{ModeEnforce, "database", "DriftCorrected Corrected labels workload-type: general -> database"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			scheme := newScheme()
+
+			// Mislabeled by hand or an old script
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:   "rke2-prod-database-xyz99-abc11",
+				Labels: map[string]string{labelKey: "general"},
+			}}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(poolRules(), node)...).Build()
+			recorder := events.NewFakeRecorder(10)
+			r := &NodeReconciler{Client: c, Scheme: scheme, Recorder: recorder, Mode: tt.mode}
+
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			updated := &corev1.Node{}
+			if err := c.Get(context.Background(), types.NamespacedName{Name: node.Name}, updated); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got := updated.Labels[labelKey]; got != tt.wantLabel {
+				t.Errorf("label %s = %q, want %q", labelKey, got, tt.wantLabel)
+			}
+			select {
+			case evt := <-recorder.Events:
+				if !strings.Contains(evt, tt.wantEvent) || tt.wantEvent == "" {
+					t.Errorf("event = %q, want %q", evt, tt.wantEvent)
+				}
+			default:
+				if tt.wantEvent != "" {
+					t.Errorf("expected event %q", tt.wantEvent)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcile_SkipsOptedOutNode(t *testing.T) {
+	scheme := newScheme()
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "rke2-prod-database-xyz99-abc11",
+		Labels:      map[string]string{labelKey: "general"},
+		Annotations: map[string]string{SkipAnnotation: "true"},
+	}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(databaseTaintRule(), node).Build()
+	recorder := events.NewFakeRecorder(10)
+	r := &NodeReconciler{Client: c, Scheme: scheme, Recorder: recorder, Mode: ModeEnforce}
+
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Node{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: node.Name}, updated); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !reflect.DeepEqual(updated.Labels, node.Labels) || len(updated.Spec.Taints) != 0 || len(updated.Annotations) != 1 {
+		t.Errorf("opted-out node changed: labels %v, taints %v, annotations %v", updated.Labels, updated.Spec.Taints, updated.Annotations)
+	}
+	select {
+	case evt := <-recorder.Events:
+		t.Errorf("unexpected event for an opted-out node: %s", evt)
+	default:
+	}
+}
+
+func TestParseEnforcementMode(t *testing.T) {
+	for _, value := range []string{"observe", "add-missing", "enforce"} {
+		if mode, err := ParseEnforcementMode(value); err != nil || string(mode) != value {
+			t.Errorf("ParseEnforcementMode(%q) = %q, %v", value, mode, err)
+		}
+	}
+	if _, err := ParseEnforcementMode("strict"); err == nil {
+		t.Error("ParseEnforcementMode(\"strict\") error = nil, want an error")
+	}
+}
+
+func TestReconcile_LabelConflict(t *testing.T) {
+	scheme := newScheme()
+
+	// Matches both the compute and the database rule at equal priority
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "rke2-prod-compute-database-01"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(poolRules(), node)...).Build()
+	recorder := events.NewFakeRecorder(10)
+	r := &NodeReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Node{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: node.Name}, updated); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if value, ok := updated.Labels[labelKey]; ok {
+		t.Errorf("label %s = %q, want it left unset on an ambiguous node", labelKey, value)
+	}
+	select {
+	case evt := <-recorder.Events:
+		want := `Warning LabelConflict Rules of equal priority and specificity disagree, not applying: ` +
+			`label workload-type: "compute" from rule compute, "database" from rule database`
+		if evt != want {
+			t.Errorf("event = %q, want %q", evt, want)
+		}
+	default:
+		t.Error("expected a LabelConflict event")
+	}
+}
+
+func TestReconcile_DryRun(t *testing.T) {
+	scheme := newScheme()
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "rke2-prod-database-xyz99-abc11",
+		Labels: map[string]string{labelKey: "general"},
+	}}
+	rule := databaseTaintRule()
+	rule.Spec.Labels = map[string]string{labelKey: "database"}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(rule, node).Build()
+	recorder := events.NewFakeRecorder(10)
+	r := &NodeReconciler{Client: c, Scheme: scheme, Recorder: recorder, Mode: ModeEnforce, DryRun: true}
+
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Node{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: node.Name}, updated); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !reflect.DeepEqual(updated.Labels, node.Labels) || len(updated.Spec.Taints) != 0 || len(updated.Annotations) != 0 {
+		t.Errorf("dry run changed the node: labels %v, taints %v, annotations %v", updated.Labels, updated.Spec.Taints, updated.Annotations)
+	}
+	select {
+	case evt := <-recorder.Events:
+		t.Errorf("unexpected event in dry run: %s", evt)
+	default:
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2026 Node Labeler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/node-labeler/node-labeler/internal/rules"
+)
+
+// Plan is what the controller would change on one node.
+type Plan struct {
+	Node string `json:"node"`
+	// Skipped is set for nodes opted out with SkipAnnotation.
+	Skipped bool `json:"skipped,omitempty"`
+	// Error is why the node could not be evaluated, e.g. its machine pool
+	// could not be read.
+	Error string `json:"error,omitempty"`
+	// Rules lists the matching rules, and the machine pool, in evaluation
+	// order.
+	Rules []string `json:"rules,omitempty"`
+	// Labels, Taints and Annotations are those the node is missing.
+	Labels      map[string]string `json:"labels,omitempty"`
+	Taints      []corev1.Taint    `json:"taints,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Drift lists the labels and annotations whose value disagrees.
+	Drift []Drift `json:"drift,omitempty"`
+	// Conflicts lists the keys tied rules disagree on; they are not applied.
+	Conflicts []rules.Conflict `json:"conflicts,omitempty"`
+}
+
+// Drift is a label or annotation the node has with another value than the
+// rules want.
+type Drift struct {
+	// Kind is "label" or "annotation".
+	Kind    string `json:"kind"`
+	Key     string `json:"key"`
+	Current string `json:"current"`
+	Want    string `json:"want"`
+	// Correct is set when the enforcement mode corrects it.
+	Correct bool `json:"correct"`
+}
+
+// plan works out what the rules and the machine pool want changed on node.
+func (r *NodeReconciler) plan(ctx context.Context, node *corev1.Node, compiled []*rules.Rule) (*Plan, error) {
+	p := &Plan{Node: node.Name}
+	if node.Annotations[SkipAnnotation] == "true" {
+		p.Skipped = true
+		return p, nil
+	}
+
+	result := rules.Evaluate(compiled, node.Name)
+	if r.Machines != nil {
+		pool, err := r.Machines.Resolve(ctx, node)
+		if err != nil {
+			return nil, fmt.Errorf("resolving machine pool: %w", err)
+		}
+		if pool != nil {
+			// The machine pool is the authoritative source; hostname rules
+			// only fill in what it does not set
+			result.Override(pool.Source, pool.Labels)
+		}
+	}
+	p.Rules = result.Matched
+	p.Conflicts = result.Conflicts
+
+	var driftedLabels, driftedAnnotations map[string]string
+	p.Labels, driftedLabels = diffKeys(node.Labels, result.Labels)
+	p.Annotations, driftedAnnotations = diffKeys(node.Annotations, result.Annotations)
+	p.Taints = missingTaints(node.Spec.Taints, result.Taints)
+	for _, kind := range []struct {
+		name          string
+		have, drifted map[string]string
+	}{{"label", node.Labels, driftedLabels}, {"annotation", node.Annotations, driftedAnnotations}} {
+		for _, key := range slices.Sorted(maps.Keys(kind.drifted)) {
+			p.Drift = append(p.Drift, Drift{
+				Kind:    kind.name,
+				Key:     key,
+				Current: kind.have[key],
+				Want:    kind.drifted[key],
+				Correct: r.Mode == ModeEnforce,
+			})
+		}
+	}
+	return p, nil
+}
+
+// Corrections returns the drift the enforcement mode corrects.
+func (p *Plan) Corrections() []Drift {
+	return slices.DeleteFunc(slices.Clone(p.Drift), func(d Drift) bool { return !d.Correct })
+}
+
+// diffKeys returns the entries of want whose keys are not in have, and those
+// whose keys are in have with another value.
+func diffKeys(have, want map[string]string) (missing, drifted map[string]string) {
+	missing, drifted = map[string]string{}, map[string]string{}
+	for key, value := range want {
+		current, ok := have[key]
+		switch {
+		case !ok:
+			missing[key] = value
+		case current != value:
+			drifted[key] = value
+		}
+	}
+	return missing, drifted
+}
+
+// formatDrift renders drift for logs and events, e.g.
+// "labels workload-type: general -> compute".
+func formatDrift(drift []Drift) string {
+	var parts []string
+	for _, kind := range []string{"label", "annotation"} {
+		var changes []string
+		for _, d := range drift {
+			if d.Kind == kind {
+				changes = append(changes, fmt.Sprintf("%s: %s -> %s", d.Key, d.Current, d.Want))
+			}
+		}
+		if len(changes) > 0 {
+			parts = append(parts, kind+"s "+strings.Join(changes, ", "))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// formatConflicts renders conflicts for logs, events and the report.
+func formatConflicts(conflicts []rules.Conflict) string {
+	rendered := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		rendered = append(rendered, conflict.String())
+	}
+	return strings.Join(rendered, "; ")
+}
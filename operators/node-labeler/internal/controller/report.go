@@ -0,0 +1,143 @@
+/*
+Copyright 2026 Node Labeler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Report is what the controller would do to every node.
+type Report struct {
+	Mode   EnforcementMode `json:"mode"`
+	DryRun bool            `json:"dryRun"`
+	Nodes  []Plan          `json:"nodes"`
+}
+
+// Report evaluates the current rules against every node, or only the node
+// named name when it is set, without changing anything. A node that cannot
+// be evaluated is reported with its error.
+func (r *NodeReconciler) Report(ctx context.Context, name string) (*Report, error) {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes); err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+	compiled, err := r.loadRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Mode: cmp.Or(r.Mode, ModeAddMissing), DryRun: r.DryRun, Nodes: []Plan{}}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if name != "" && node.Name != name {
+			continue
+		}
+		plan, err := r.plan(ctx, node, compiled)
+		if err != nil {
+			plan = &Plan{Node: node.Name, Error: err.Error()}
+		}
+		report.Nodes = append(report.Nodes, *plan)
+	}
+	slices.SortFunc(report.Nodes, func(a, b Plan) int { return strings.Compare(a.Node, b.Node) })
+	return report, nil
+}
+
+// ReportHandler serves Report, read-only:
+//
+//	GET /debug/nodes                     every node as JSON
+//	GET /debug/nodes?format=table        every node as a text table
+//	GET /debug/nodes?node=rke2-prod-...  one node
+func (r *NodeReconciler) ReportHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		q := req.URL.Query()
+		format := cmp.Or(q.Get("format"), "json")
+		if format != "json" && format != "table" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "format must be json or table"})
+			return
+		}
+		report, err := r.Report(req.Context(), q.Get("node"))
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if format == "table" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			report.WriteTable(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	})
+}
+
+// WriteTable renders the report with one row per node.
+func (rep *Report) WriteTable(w io.Writer) {
+	fmt.Fprintf(w, "# mode=%s dryRun=%t\n", rep.Mode, rep.DryRun)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tSTATUS\tRULES\tADD\tDRIFT\tCONFLICTS")
+	for _, p := range rep.Nodes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", p.Node, p.Status(),
+			orDash(strings.Join(p.Rules, ",")),
+			orDash(formatApplied(p.Labels, p.Taints, p.Annotations)),
+			orDash(formatDrift(p.Drift)),
+			orDash(formatConflicts(p.Conflicts)))
+	}
+	_ = tw.Flush()
+}
+
+// Status summarizes a plan: skipped, error, conflict, pending (something to
+// add or correct), drift (drift left alone by the mode) or in-sync.
+func (p *Plan) Status() string {
+	switch {
+	case p.Skipped:
+		return "skipped"
+	case p.Error != "":
+		return "error: " + p.Error
+	case len(p.Conflicts) > 0:
+		return "conflict"
+	case len(p.Labels) > 0 || len(p.Taints) > 0 || len(p.Annotations) > 0 || len(p.Corrections()) > 0:
+		return "pending"
+	case len(p.Drift) > 0:
+		return "drift"
+	}
+	return "in-sync"
+}
+
+func orDash(s string) string {
+	return cmp.Or(s, "-")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2026 Node Labeler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newReportServer(t *testing.T, mode EnforcementMode) *httptest.Server {
+	t.Helper()
+	scheme := newScheme()
+	nodes := []*corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "rke2-prod-general-xyz99-aaa11"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "rke2-prod-compute-xyz99-bbb22", Labels: map[string]string{labelKey: "general"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "rke2-prod-compute-database-01"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "rke2-prod-database-xyz99-ccc33", Annotations: map[string]string{SkipAnnotation: "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "rke2-prod-cp-xyz99-ddd44"}},
+	}
+	objs := poolRules()
+	for _, node := range nodes {
+		objs = append(objs, node)
+	}
+	r := &NodeReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Scheme:   scheme,
+		Recorder: events.NewFakeRecorder(10),
+		Mode:     mode,
+		DryRun:   true,
+	}
+	server := httptest.NewServer(r.ReportHandler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestReportHandler_JSON(t *testing.T) {
+	server := newReportServer(t, ModeEnforce)
+
+	resp, err := http.Get(server.URL + "/debug/nodes")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	var report Report
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+
+	if report.Mode != ModeEnforce || !report.DryRun {
+		t.Errorf("mode = %s, dryRun = %t, want enforce and true", report.Mode, report.DryRun)
+	}
+	want := map[string]string{
+		"rke2-prod-compute-database-01":  "conflict",
+		"rke2-prod-compute-xyz99-bbb22":  "pending",
+		"rke2-prod-cp-xyz99-ddd44":       "in-sync",
+		"rke2-prod-database-xyz99-ccc33": "skipped",
+		"rke2-prod-general-xyz99-aaa11":  "pending",
+	}
+	if len(report.Nodes) != len(want) {
+		t.Fatalf("got %d nodes, want %d", len(report.Nodes), len(want))
+	}
+	for i, p := range report.Nodes {
+		if i > 0 && report.Nodes[i-1].Node > p.Node {
+			t.Errorf("nodes not sorted by name: %s before %s", report.Nodes[i-1].Node, p.Node)
+		}
+		if got := p.Status(); got != want[p.Node] {
+			t.Errorf("%s status = %q, want %q", p.Node, got, want[p.Node])
+		}
+	}
+
+	compute := report.Nodes[1]
+	if len(compute.Drift) != 1 || compute.Drift[0] != (Drift{Kind: "label", Key: labelKey, Current: "general", Want: "compute", Correct: true}) {
+		t.Errorf("compute drift = %+v, want workload-type general -> compute, corrected", compute.Drift)
+	}
+	if general := report.Nodes[4]; general.Labels[labelKey] != "general" || general.Rules[0] != "general" {
+		t.Errorf("general plan = %+v, want workload-type=general from rule general", general)
+	}
+}
+
+func TestReportHandler_Table(t *testing.T) {
+	server := newReportServer(t, ModeAddMissing)
+
+	resp, err := http.Get(server.URL + "/debug/nodes?format=table&node=rke2-prod-compute-xyz99-bbb22")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("table = %q, want a mode line, a header and one node", body)
+	}
+	if lines[0] != "# mode=add-missing dryRun=true" || !strings.HasPrefix(lines[1], "NODE") {
+		t.Errorf("table head = %q", lines[:2])
+	}
+	// add-missing leaves the wrong label alone, so it is drift, not pending
+	if fields := strings.Fields(lines[2]); fields[0] != "rke2-prod-compute-xyz99-bbb22" || fields[1] != "drift" {
+		t.Errorf("row = %q, want the compute node with status drift", lines[2])
+	}
+	if !strings.Contains(lines[2], "labels workload-type: general -> compute") {
+		t.Errorf("row = %q, want the drift described", lines[2])
+	}
+}
+
+func TestReportHandler_RejectsBadRequests(t *testing.T) {
+	server := newReportServer(t, ModeAddMissing)
+
+	resp, err := http.Get(server.URL + "/debug/nodes?format=yaml")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("format=yaml status = %d, want 400", resp.StatusCode)
+	}
+
+	resp, err = http.Post(server.URL+"/debug/nodes", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", resp.StatusCode)
+	}
+}
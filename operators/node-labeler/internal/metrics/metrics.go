@@ -24,7 +24,27 @@ import (
 var (
 	LabelsAppliedTotal = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "node_labeler_labels_applied_total",
-		Help: "Total number of workload-type labels applied to nodes",
+		Help: "Total number of labels applied to nodes",
+	})
+
+	TaintsAppliedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "node_labeler_taints_applied_total",
+		Help: "Total number of taints added to nodes",
+	})
+
+	AnnotationsAppliedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "node_labeler_annotations_applied_total",
+		Help: "Total number of annotations applied to nodes",
+	})
+
+	DriftCorrectionsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "node_labeler_drift_corrections_total",
+		Help: "Total number of node labels and annotations corrected to match the rules",
+	})
+
+	LabelConflictsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "node_labeler_label_conflicts_total",
+		Help: "Total number of keys left unapplied because rules of equal priority and specificity disagree",
 	})
 
 	ErrorsTotal = prometheus.NewCounter(prometheus.CounterOpts{
@@ -34,5 +54,5 @@ var (
 )
 
 func init() {
-	metrics.Registry.MustRegister(LabelsAppliedTotal, ErrorsTotal)
+	metrics.Registry.MustRegister(LabelsAppliedTotal, TaintsAppliedTotal, AnnotationsAppliedTotal, DriftCorrectionsTotal, LabelConflictsTotal, ErrorsTotal)
 }
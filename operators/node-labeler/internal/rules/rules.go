@@ -0,0 +1,315 @@
+/*
+Copyright 2026 Node Labeler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rules evaluates NodeLabelRules against node hostnames.
+package rules
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	labelingv1alpha1 "github.com/node-labeler/node-labeler/api/v1alpha1"
+)
+
+// Specificity ranks how much of a hostname an expression pins down. At equal
+// priority, the rule with the more specific match wins.
+type Specificity int
+
+const (
+	// Partial expressions find a fragment of the hostname: substrings and
+	// unanchored regular expressions.
+	Partial Specificity = iota + 1
+	// Whole expressions describe the whole hostname: globs and regular
+	// expressions anchored with ^ and $.
+	Whole
+)
+
+// Rule is a NodeLabelRule with its hostname expressions compiled.
+type Rule struct {
+	Name        string
+	Priority    int32
+	Labels      map[string]string
+	Taints      []corev1.Taint
+	Annotations map[string]string
+
+	matchers []matcher
+}
+
+type matcher struct {
+	match       func(hostname string) bool
+	specificity Specificity
+}
+
+// Compile prepares rule for matching. It fails on an invalid glob or
+// regular expression, or an expression with no field set.
+func Compile(rule *labelingv1alpha1.NodeLabelRule) (*Rule, error) {
+	c := &Rule{
+		Name:        rule.Name,
+		Priority:    rule.Spec.Priority,
+		Labels:      rule.Spec.Labels,
+		Taints:      rule.Spec.Taints,
+		Annotations: rule.Spec.Annotations,
+	}
+	for i, expr := range rule.Spec.Hostnames {
+		switch {
+		case expr.Substring != nil:
+			substring := *expr.Substring
+			c.matchers = append(c.matchers, matcher{func(h string) bool { return strings.Contains(h, substring) }, Partial})
+		case expr.Glob != nil:
+			glob := *expr.Glob
+			if _, err := path.Match(glob, ""); err != nil {
+				return nil, fmt.Errorf("hostnames[%d].glob %q: %w", i, glob, err)
+			}
+			c.matchers = append(c.matchers, matcher{func(h string) bool {
+				ok, _ := path.Match(glob, h)
+				return ok
+			}, Whole})
+		case expr.Regex != nil:
+			re, err := regexp.Compile(*expr.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("hostnames[%d].regex: %w", i, err)
+			}
+			specificity := Partial
+			if anchored(*expr.Regex) {
+				specificity = Whole
+			}
+			c.matchers = append(c.matchers, matcher{re.MatchString, specificity})
+		default:
+			return nil, fmt.Errorf("hostnames[%d] sets none of substring, glob and regex", i)
+		}
+	}
+	return c, nil
+}
+
+// anchored reports whether a regular expression only matches whole strings,
+// i.e. starts with ^ and ends with $.
+func anchored(expr string) bool {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return false
+	}
+	re = re.Simplify()
+	return re.Op == syntax.OpConcat && len(re.Sub) > 1 &&
+		re.Sub[0].Op == syntax.OpBeginText && re.Sub[len(re.Sub)-1].Op == syntax.OpEndText
+}
+
+// Matches reports whether any of the rule's expressions matches hostname.
+func (r *Rule) Matches(hostname string) bool {
+	return r.Specificity(hostname) > 0
+}
+
+// Specificity returns the highest specificity of the rule's expressions that
+// match hostname, or 0 if none does.
+func (r *Rule) Specificity(hostname string) Specificity {
+	var best Specificity
+	for _, m := range r.matchers {
+		if m.specificity > best && m.match(hostname) {
+			best = m.specificity
+		}
+	}
+	return best
+}
+
+// Result is what a set of rules applies to one node.
+type Result struct {
+	// Labels maps each label key to the value of the rule that won it.
+	Labels map[string]string
+	// LabelSources maps each label key to the name of the rule that won it.
+	LabelSources map[string]string
+	// Annotations maps each annotation key to the value of the rule that won it.
+	Annotations map[string]string
+	// AnnotationSources maps each annotation key to the rule that won it.
+	AnnotationSources map[string]string
+	// Taints holds one taint per key and effect, in evaluation order.
+	Taints []corev1.Taint
+	// TaintSources maps each TaintID to the rule that won it.
+	TaintSources map[string]string
+	// Conflicts lists the keys that no rule won, sorted by kind and key.
+	Conflicts []Conflict
+	// Matched lists the matching rules in evaluation order.
+	Matched []string
+}
+
+// Conflict is a key that matching rules of the same priority and
+// specificity set to different values. It is applied from none of them.
+type Conflict struct {
+	// Kind is "label", "annotation" or "taint".
+	Kind string `json:"kind"`
+	// Key is the label or annotation key, or the TaintID.
+	Key string `json:"key"`
+	// Rules and Values are the tied rules and the value each sets.
+	Rules  []string `json:"rules"`
+	Values []string `json:"values"`
+}
+
+func (c Conflict) String() string {
+	sets := make([]string, 0, len(c.Rules))
+	for i, rule := range c.Rules {
+		sets = append(sets, fmt.Sprintf("%q from rule %s", c.Values[i], rule))
+	}
+	return fmt.Sprintf("%s %s: %s", c.Kind, c.Key, strings.Join(sets, ", "))
+}
+
+// Override sets labels from an authoritative source, such as the node's
+// machine pool, over those the rules chose. It settles any conflict on
+// their keys.
+func (r *Result) Override(source string, labels map[string]string) {
+	for key, value := range labels {
+		r.Labels[key] = value
+		r.LabelSources[key] = source
+	}
+	r.Conflicts = slices.DeleteFunc(r.Conflicts, func(c Conflict) bool {
+		_, overridden := labels[c.Key]
+		return c.Kind == "label" && overridden
+	})
+	r.Matched = append(r.Matched, source)
+}
+
+// TaintID identifies a taint on a node: its key and effect. The API server
+// rejects a node with two taints sharing both.
+func TaintID(t corev1.Taint) string {
+	return t.Key + ":" + string(t.Effect)
+}
+
+// Evaluate returns what rules apply to the node named hostname. Matching
+// rules are ranked by priority, highest first, then by specificity, and the
+// highest-ranked rule to set a label key, an annotation key or a taint key
+// and effect wins it. When rules of equal rank set the key to different
+// values, it is reported as a Conflict and not applied. The result does not
+// depend on the order of rules.
+func Evaluate(rules []*Rule, hostname string) Result {
+	type match struct {
+		rule *Rule
+		rank rank
+	}
+	var matched []match
+	for _, rule := range rules {
+		if specificity := rule.Specificity(hostname); specificity > 0 {
+			matched = append(matched, match{rule, rank{rule.Priority, specificity}})
+		}
+	}
+	slices.SortFunc(matched, func(a, b match) int {
+		return cmp.Or(
+			cmp.Compare(b.rank.priority, a.rank.priority),
+			cmp.Compare(b.rank.specificity, a.rank.specificity),
+			strings.Compare(a.rule.Name, b.rule.Name),
+		)
+	})
+
+	res := Result{
+		Labels:            map[string]string{},
+		LabelSources:      map[string]string{},
+		Annotations:       map[string]string{},
+		AnnotationSources: map[string]string{},
+		TaintSources:      map[string]string{},
+	}
+	labels := newClaims("label", res.Labels, res.LabelSources)
+	annotations := newClaims("annotation", res.Annotations, res.AnnotationSources)
+	taints := newClaims("taint", map[string]string{}, res.TaintSources)
+	winners := map[string]corev1.Taint{}
+	for _, m := range matched {
+		res.Matched = append(res.Matched, m.rule.Name)
+		for key, value := range m.rule.Labels {
+			labels.add(key, value, m.rule.Name, m.rank)
+		}
+		for key, value := range m.rule.Annotations {
+			annotations.add(key, value, m.rule.Name, m.rank)
+		}
+		for _, taint := range m.rule.Taints {
+			id := TaintID(taint)
+			if _, taken := winners[id]; !taken {
+				winners[id] = taint
+			}
+			taints.add(id, taint.Value, m.rule.Name, m.rank)
+		}
+	}
+
+	res.Conflicts = append(res.Conflicts, labels.resolve()...)
+	res.Conflicts = append(res.Conflicts, annotations.resolve()...)
+	res.Conflicts = append(res.Conflicts, taints.resolve()...)
+	for _, id := range taints.order {
+		if _, ok := taints.values[id]; ok {
+			res.Taints = append(res.Taints, winners[id])
+		}
+	}
+	return res
+}
+
+type rank struct {
+	priority    int32
+	specificity Specificity
+}
+
+// claims tracks which rule won each key of one kind, and the ties.
+type claims struct {
+	kind       string
+	values     map[string]string
+	sources    map[string]string
+	ranks      map[string]rank
+	ties       map[string]*Conflict
+	conflicted map[string]bool
+	order      []string
+}
+
+func newClaims(kind string, values, sources map[string]string) *claims {
+	return &claims{
+		kind:       kind,
+		values:     values,
+		sources:    sources,
+		ranks:      map[string]rank{},
+		ties:       map[string]*Conflict{},
+		conflicted: map[string]bool{},
+	}
+}
+
+// add claims key for rule. Rules must be added highest rank first. Every
+// rule of the winning rank is remembered, so that a conflict lists the rules
+// agreeing with the first claimant as well.
+func (c *claims) add(key, value, rule string, r rank) {
+	current, taken := c.values[key]
+	switch {
+	case !taken:
+		c.values[key], c.sources[key], c.ranks[key] = value, rule, r
+		c.ties[key] = &Conflict{Kind: c.kind, Key: key, Rules: []string{rule}, Values: []string{value}}
+		c.order = append(c.order, key)
+	case c.ranks[key] == r:
+		tie := c.ties[key]
+		tie.Rules = append(tie.Rules, rule)
+		tie.Values = append(tie.Values, value)
+		if current != value {
+			c.conflicted[key] = true
+		}
+	}
+}
+
+// resolve drops the conflicting keys and returns their conflicts by key.
+func (c *claims) resolve() []Conflict {
+	var conflicts []Conflict
+	for _, key := range slices.Sorted(maps.Keys(c.conflicted)) {
+		delete(c.values, key)
+		delete(c.sources, key)
+		conflicts = append(conflicts, *c.ties[key])
+	}
+	return conflicts
+}
@@ -0,0 +1,303 @@
+/*
+Copyright 2026 Node Labeler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"maps"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	labelingv1alpha1 "github.com/node-labeler/node-labeler/api/v1alpha1"
+)
+
+func ptr(s string) *string { return &s }
+
+func newRule(name string, priority int32, labels map[string]string, exprs ...labelingv1alpha1.HostnameExpression) *labelingv1alpha1.NodeLabelRule {
+	return &labelingv1alpha1.NodeLabelRule{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: labelingv1alpha1.NodeLabelRuleSpec{
+			Hostnames: exprs,
+			Labels:    labels,
+			Priority:  priority,
+		},
+	}
+}
+
+func mustCompile(t *testing.T, rule *labelingv1alpha1.NodeLabelRule) *Rule {
+	t.Helper()
+	c, err := Compile(rule)
+	if err != nil {
+		t.Fatalf("Compile(%s) error = %v", rule.Name, err)
+	}
+	return c
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     labelingv1alpha1.HostnameExpression
+		hostname string
+		want     bool
+	}{
+		{"substring", labelingv1alpha1.HostnameExpression{Substring: ptr("-compute-")}, "rke2-prod-compute-abc12", true},
+		{"substring miss", labelingv1alpha1.HostnameExpression{Substring: ptr("-compute-")}, "rke2-prod-cp-abc12", false},
+		{"glob", labelingv1alpha1.HostnameExpression{Glob: ptr("rke2-*-gpu-?????-*")}, "rke2-prod-gpu-abc12-def34", true},
+		{"glob is anchored", labelingv1alpha1.HostnameExpression{Glob: ptr("gpu-*")}, "rke2-gpu-abc12", false},
+		{"regex", labelingv1alpha1.HostnameExpression{Regex: ptr(`-(db|database)-[a-z0-9]{5}-`)}, "rke2-prod-db-abc12-def34", true},
+		{"regex miss", labelingv1alpha1.HostnameExpression{Regex: ptr(`^db-`)}, "rke2-prod-db-abc12", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := mustCompile(t, newRule("r", 0, map[string]string{"k": "v"}, tt.expr))
+			if got := rule.Matches(tt.hostname); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", tt.hostname, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile_RejectsInvalid(t *testing.T) {
+	tests := map[string]labelingv1alpha1.HostnameExpression{
+		"bad regex": {Regex: ptr("(")},
+		"bad glob":  {Glob: ptr("[")},
+		"empty":     {},
+	}
+	for name, expr := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Compile(newRule("r", 0, nil, expr)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestEvaluate_Priority(t *testing.T) {
+	rules := []*Rule{
+		mustCompile(t, newRule("compute", 0, map[string]string{"workload-type": "compute", "tier": "batch"},
+			labelingv1alpha1.HostnameExpression{Substring: ptr("-compute-")})),
+		mustCompile(t, newRule("gpu", 10, map[string]string{"workload-type": "gpu"},
+			labelingv1alpha1.HostnameExpression{Glob: ptr("*-compute-gpu-*")})),
+		mustCompile(t, newRule("database", 0, map[string]string{"workload-type": "database"},
+			labelingv1alpha1.HostnameExpression{Substring: ptr("-database-")})),
+	}
+
+	res := Evaluate(rules, "rke2-prod-compute-gpu-abc12")
+	want := map[string]string{"workload-type": "gpu", "tier": "batch"}
+	if !maps.Equal(res.Labels, want) {
+		t.Errorf("Labels = %v, want %v", res.Labels, want)
+	}
+	if res.LabelSources["workload-type"] != "gpu" || res.LabelSources["tier"] != "compute" {
+		t.Errorf("LabelSources = %v, want workload-type from gpu and tier from compute", res.LabelSources)
+	}
+	if !slices.Equal(res.Matched, []string{"gpu", "compute"}) {
+		t.Errorf("Matched = %v, want [gpu compute]", res.Matched)
+	}
+
+	if res := Evaluate(rules, "rke2-prod-cp-abc12"); len(res.Labels) != 0 || len(res.Matched) != 0 {
+		t.Errorf("control plane node = %+v, want no match", res)
+	}
+}
+
+func TestEvaluate_TaintsAndAnnotations(t *testing.T) {
+	database := newRule("database", 0, nil, labelingv1alpha1.HostnameExpression{Substring: ptr("-database-")})
+	database.Spec.Taints = []corev1.Taint{
+		{Key: "workload-type", Value: "database", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "workload-type", Value: "database", Effect: corev1.TaintEffectPreferNoSchedule},
+	}
+	database.Spec.Annotations = map[string]string{"cluster-autoscaler.kubernetes.io/scale-down-disabled": "true"}
+	primary := newRule("database-primary", 5, nil, labelingv1alpha1.HostnameExpression{Glob: ptr("*-database-primary-*")})
+	primary.Spec.Taints = []corev1.Taint{{Key: "workload-type", Value: "database-primary", Effect: corev1.TaintEffectNoSchedule}}
+	primary.Spec.Annotations = map[string]string{"cluster-autoscaler.kubernetes.io/scale-down-disabled": "false"}
+
+	res := Evaluate([]*Rule{mustCompile(t, database), mustCompile(t, primary)}, "rke2-prod-database-primary-abc12")
+	want := []corev1.Taint{
+		{Key: "workload-type", Value: "database-primary", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "workload-type", Value: "database", Effect: corev1.TaintEffectPreferNoSchedule},
+	}
+	if !slices.Equal(res.Taints, want) {
+		t.Errorf("Taints = %v, want %v", res.Taints, want)
+	}
+	if res.TaintSources["workload-type:NoSchedule"] != "database-primary" {
+		t.Errorf("TaintSources = %v, want NoSchedule from database-primary", res.TaintSources)
+	}
+	if got := res.Annotations["cluster-autoscaler.kubernetes.io/scale-down-disabled"]; got != "false" {
+		t.Errorf("scale-down-disabled = %q, want the higher-priority rule's %q", got, "false")
+	}
+}
+
+func TestSpecificity(t *testing.T) {
+	tests := []struct {
+		name string
+		expr labelingv1alpha1.HostnameExpression
+		want Specificity
+	}{
+		{"substring", labelingv1alpha1.HostnameExpression{Substring: ptr("-compute-")}, Partial},
+		{"glob", labelingv1alpha1.HostnameExpression{Glob: ptr("*-compute-*")}, Whole},
+		{"unanchored regex", labelingv1alpha1.HostnameExpression{Regex: ptr(`-compute-\d+`)}, Partial},
+		{"start anchored regex", labelingv1alpha1.HostnameExpression{Regex: ptr(`^prod-compute-`)}, Partial},
+		{"anchored regex", labelingv1alpha1.HostnameExpression{Regex: ptr(`^prod-compute-.*$`)}, Whole},
+		{"anchored alternation", labelingv1alpha1.HostnameExpression{Regex: ptr(`^prod-(compute|batch)-\d+$`)}, Whole},
+		{"no match", labelingv1alpha1.HostnameExpression{Substring: ptr("-gpu-")}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := mustCompile(t, newRule("r", 0, map[string]string{"k": "v"}, tt.expr))
+			if got := rule.Specificity("prod-compute-01"); got != tt.want {
+				t.Errorf("Specificity() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluate_Conflicts(t *testing.T) {
+	substring := func(s string) labelingv1alpha1.HostnameExpression {
+		return labelingv1alpha1.HostnameExpression{Substring: ptr(s)}
+	}
+	compute := newRule("compute", 0, map[string]string{"workload-type": "compute", "pool": "shared"}, substring("-compute-"))
+	database := newRule("database", 0, map[string]string{"workload-type": "database", "pool": "shared"}, substring("-database-"))
+
+	tests := []struct {
+		name          string
+		rules         []*labelingv1alpha1.NodeLabelRule
+		wantLabels    map[string]string
+		wantConflicts []string
+	}{
+		{
+			name:          "two pools in the hostname",
+			rules:         []*labelingv1alpha1.NodeLabelRule{compute, database},
+			wantLabels:    map[string]string{"pool": "shared"},
+			wantConflicts: []string{`label workload-type: "compute" from rule compute, "database" from rule database`},
+		},
+		{
+			name: "priority settles it",
+			rules: []*labelingv1alpha1.NodeLabelRule{compute,
+				newRule("database", 1, map[string]string{"workload-type": "database"}, substring("-database-"))},
+			wantLabels: map[string]string{"workload-type": "database", "pool": "shared"},
+		},
+		{
+			name: "whole-hostname match settles it",
+			rules: []*labelingv1alpha1.NodeLabelRule{compute,
+				newRule("database", 0, map[string]string{"workload-type": "database"},
+					labelingv1alpha1.HostnameExpression{Glob: ptr("prod-compute-database-*")})},
+			wantLabels: map[string]string{"workload-type": "database", "pool": "shared"},
+		},
+		{
+			name: "lower rank is overridden, not a conflict",
+			rules: []*labelingv1alpha1.NodeLabelRule{compute, database,
+				newRule("pinned", 5, map[string]string{"workload-type": "analytics"}, substring("prod-"))},
+			wantLabels: map[string]string{"workload-type": "analytics", "pool": "shared"},
+		},
+		{
+			name: "three-way tie lists every rule",
+			rules: []*labelingv1alpha1.NodeLabelRule{compute, database,
+				newRule("prod", 0, map[string]string{"workload-type": "compute"}, substring("prod-"))},
+			wantLabels: map[string]string{"pool": "shared"},
+			wantConflicts: []string{
+				`label workload-type: "compute" from rule compute, "database" from rule database, "compute" from rule prod`,
+			},
+		},
+		{
+			name: "agreeing tied rule is listed too",
+			rules: []*labelingv1alpha1.NodeLabelRule{compute, database,
+				newRule("cpu", 0, map[string]string{"workload-type": "compute"}, substring("prod-"))},
+			wantLabels: map[string]string{"pool": "shared"},
+			wantConflicts: []string{
+				`label workload-type: "compute" from rule compute, "compute" from rule cpu, "database" from rule database`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rules []*Rule
+			for _, rule := range tt.rules {
+				rules = append(rules, mustCompile(t, rule))
+			}
+			res := Evaluate(rules, "prod-compute-database-01")
+			if !maps.Equal(res.Labels, tt.wantLabels) {
+				t.Errorf("Labels = %v, want %v", res.Labels, tt.wantLabels)
+			}
+			var conflicts []string
+			for _, c := range res.Conflicts {
+				conflicts = append(conflicts, c.String())
+			}
+			if !slices.Equal(conflicts, tt.wantConflicts) {
+				t.Errorf("Conflicts = %q, want %q", conflicts, tt.wantConflicts)
+			}
+		})
+	}
+}
+
+func TestEvaluate_ConflictingTaintsAndAnnotations(t *testing.T) {
+	a := newRule("a", 0, nil, labelingv1alpha1.HostnameExpression{Substring: ptr("-database-")})
+	a.Spec.Taints = []corev1.Taint{
+		{Key: "dedicated", Value: "database", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "dedicated", Value: "database", Effect: corev1.TaintEffectPreferNoSchedule},
+	}
+	a.Spec.Annotations = map[string]string{"owner": "dba"}
+	b := newRule("b", 0, nil, labelingv1alpha1.HostnameExpression{Substring: ptr("-compute-")})
+	b.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "compute", Effect: corev1.TaintEffectNoSchedule}}
+	b.Spec.Annotations = map[string]string{"owner": "platform"}
+
+	res := Evaluate([]*Rule{mustCompile(t, a), mustCompile(t, b)}, "prod-compute-database-01")
+	wantTaints := []corev1.Taint{{Key: "dedicated", Value: "database", Effect: corev1.TaintEffectPreferNoSchedule}}
+	if !slices.Equal(res.Taints, wantTaints) {
+		t.Errorf("Taints = %v, want only the uncontested %v", res.Taints, wantTaints)
+	}
+	if _, ok := res.TaintSources["dedicated:NoSchedule"]; ok {
+		t.Errorf("TaintSources = %v, want no source for the conflicting taint", res.TaintSources)
+	}
+	if len(res.Annotations) != 0 {
+		t.Errorf("Annotations = %v, want none", res.Annotations)
+	}
+	var kinds []string
+	for _, c := range res.Conflicts {
+		kinds = append(kinds, c.Kind+" "+c.Key)
+	}
+	if want := []string{"annotation owner", "taint dedicated:NoSchedule"}; !slices.Equal(kinds, want) {
+		t.Errorf("Conflicts = %v, want %v", kinds, want)
+	}
+}
+
+func TestEvaluate_OrderIndependent(t *testing.T) {
+	var rules []*Rule
+	for i, pool := range []string{"compute", "database", "prod", "01"} {
+		rules = append(rules, mustCompile(t, newRule(pool, int32(i%2), map[string]string{"workload-type": pool, pool: "true"},
+			labelingv1alpha1.HostnameExpression{Substring: ptr(pool)})))
+	}
+
+	want := Evaluate(rules, "prod-compute-database-01")
+	for i := 0; i < 20; i++ {
+		shuffled := slices.Clone(rules)
+		rand.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+		if got := Evaluate(shuffled, "prod-compute-database-01"); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Evaluate(%v) = %+v, want %+v regardless of rule order", names(shuffled), got, want)
+		}
+	}
+}
+
+func names(rules []*Rule) []string {
+	var out []string
+	for _, rule := range rules {
+		out = append(out, rule.Name)
+	}
+	return out
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+)
+
+// The field-for-field struct conversions below stop compiling if the two
+// versions drift apart, which is the point: every v1alpha1 field must have a
+// home in v1beta1 so a round trip loses nothing. The v1beta1 fields v1alpha1
+// has no home for are kept in the AnnotationV1beta1Spec annotation instead.
+
+// AnnotationV1beta1Spec holds, as JSON, the v1beta1 spec fields of an object
+// read as v1alpha1, so writing it back does not clear them.
+const AnnotationV1beta1Spec = "volume-autoscaler.io/v1beta1-spec"
+
+// v1beta1OnlySpec lists the v1beta1 spec fields without a v1alpha1 equivalent.
+type v1beta1OnlySpec struct {
+	Forecast       *v1beta1.ForecastSpec   `json:"forecast,omitempty"`
+	MaxIncrement   *resource.Quantity      `json:"maxIncrement,omitempty"`
+	Steps          []v1beta1.GrowthStep    `json:"steps,omitempty"`
+	Budget         *v1beta1.BudgetSpec     `json:"budget,omitempty"`
+	PrometheusAuth *v1beta1.PrometheusAuth `json:"prometheusAuth,omitempty"`
+}
+
+// ConvertTo converts this VolumeAutoscaler to the v1beta1 hub.
+func (src *VolumeAutoscaler) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.VolumeAutoscaler)
+	dst.ObjectMeta = src.ObjectMeta
+
+	s := src.Spec
+	dst.Spec = v1beta1.VolumeAutoscalerSpec{
+		Target: v1beta1.VolumeAutoscalerTarget{
+			PVCName:  s.Target.PVCName,
+			Selector: s.Target.Selector,
+			OwnerRef: (*v1beta1.VolumeAutoscalerOwnerRef)(s.Target.OwnerRef),
+		},
+		Suspend: s.Suspend,
+		Trigger: v1beta1.TriggerSpec{
+			ThresholdPercent:          s.ThresholdPercent,
+			MinFreeBytes:              s.MinFreeBytes,
+			Mode:                      v1beta1.ThresholdMode(s.ThresholdMode),
+			EmergencyThresholdPercent: s.EmergencyThresholdPercent,
+			InodeThresholdPercent:     s.InodeThresholdPercent,
+		},
+		Growth: v1beta1.GrowthSpec{
+			IncreasePercent:          s.IncreasePercent,
+			MinIncrement:             s.IncreaseMinimum,
+			EmergencyIncreasePercent: s.EmergencyIncreasePercent,
+			CooldownPeriod:           s.CooldownPeriod,
+		},
+		Limits: v1beta1.LimitsSpec{MaxSize: s.MaxSize},
+		Source: v1beta1.SourceSpec{
+			// v1alpha1 predates backend selection and only reads Prometheus.
+			Backend:      v1beta1.MetricsBackendPrometheus,
+			Prometheus:   v1beta1.PrometheusSource{URL: s.PrometheusURL},
+			PollInterval: s.PollInterval,
+		},
+		AnomalyDetection: (*v1beta1.AnomalyDetection)(s.AnomalyDetection),
+	}
+	if raw, ok := src.Annotations[AnnotationV1beta1Spec]; ok {
+		var only v1beta1OnlySpec
+		if err := json.Unmarshal([]byte(raw), &only); err != nil {
+			return fmt.Errorf("decoding %s annotation: %w", AnnotationV1beta1Spec, err)
+		}
+		dst.Spec.Trigger.Forecast = only.Forecast
+		dst.Spec.Growth.MaxIncrement = only.MaxIncrement
+		dst.Spec.Growth.Steps = only.Steps
+		dst.Spec.Limits.Budget = only.Budget
+		dst.Spec.Source.Prometheus.Auth = only.PrometheusAuth
+		dst.Annotations = maps.Clone(src.Annotations)
+		delete(dst.Annotations, AnnotationV1beta1Spec)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+
+	st := src.Status
+	dst.Status = v1beta1.VolumeAutoscalerStatus{
+		Conditions:         st.Conditions,
+		LastPollTime:       st.LastPollTime,
+		TotalScaleEvents:   st.TotalScaleEvents,
+		Cost:               (*v1beta1.CostStatus)(st.Cost),
+		ObservedGeneration: st.ObservedGeneration,
+	}
+	for _, p := range st.PVCs {
+		pvc := v1beta1.PVCStatus{
+			Name:                    p.Name,
+			CurrentSize:             p.CurrentSize,
+			UsageBytes:              p.UsageBytes,
+			UsagePercent:            p.UsagePercent,
+			FreeBytes:               p.FreeBytes,
+			TriggeredBy:             p.TriggeredBy,
+			LastScaleTime:           p.LastScaleTime,
+			LastScaleSize:           p.LastScaleSize,
+			Anomaly:                 (*v1beta1.PVCAnomaly)(p.Anomaly),
+			Unhealthy:               (*v1beta1.PVCHealth)(p.Unhealthy),
+			AnomalyAcknowledgedTime: p.AnomalyAcknowledgedTime,
+		}
+		for _, e := range p.RecentExpansions {
+			pvc.RecentExpansions = append(pvc.RecentExpansions, v1beta1.PVCExpansion(e))
+		}
+		dst.Status.PVCs = append(dst.Status.PVCs, pvc)
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1beta1 hub to this version.
+func (dst *VolumeAutoscaler) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.VolumeAutoscaler)
+	dst.ObjectMeta = src.ObjectMeta
+
+	s := src.Spec
+	dst.Spec = VolumeAutoscalerSpec{
+		Target: VolumeAutoscalerTarget{
+			PVCName:  s.Target.PVCName,
+			Selector: s.Target.Selector,
+			OwnerRef: (*VolumeAutoscalerOwnerRef)(s.Target.OwnerRef),
+		},
+		Suspend:                   s.Suspend,
+		ThresholdPercent:          s.Trigger.ThresholdPercent,
+		MinFreeBytes:              s.Trigger.MinFreeBytes,
+		ThresholdMode:             ThresholdMode(s.Trigger.Mode),
+		EmergencyThresholdPercent: s.Trigger.EmergencyThresholdPercent,
+		InodeThresholdPercent:     s.Trigger.InodeThresholdPercent,
+		IncreasePercent:           s.Growth.IncreasePercent,
+		IncreaseMinimum:           s.Growth.MinIncrement,
+		EmergencyIncreasePercent:  s.Growth.EmergencyIncreasePercent,
+		CooldownPeriod:            s.Growth.CooldownPeriod,
+		MaxSize:                   s.Limits.MaxSize,
+		PrometheusURL:             s.Source.Prometheus.URL,
+		PollInterval:              s.Source.PollInterval,
+		AnomalyDetection:          (*AnomalyDetection)(s.AnomalyDetection),
+	}
+	only := v1beta1OnlySpec{
+		Forecast:       s.Trigger.Forecast,
+		MaxIncrement:   s.Growth.MaxIncrement,
+		Steps:          s.Growth.Steps,
+		Budget:         s.Limits.Budget,
+		PrometheusAuth: s.Source.Prometheus.Auth,
+	}
+	if !reflect.ValueOf(only).IsZero() {
+		raw, err := json.Marshal(only)
+		if err != nil {
+			return fmt.Errorf("encoding %s annotation: %w", AnnotationV1beta1Spec, err)
+		}
+		dst.Annotations = maps.Clone(src.Annotations)
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[AnnotationV1beta1Spec] = string(raw)
+	}
+
+	st := src.Status
+	dst.Status = VolumeAutoscalerStatus{
+		Conditions:         st.Conditions,
+		LastPollTime:       st.LastPollTime,
+		TotalScaleEvents:   st.TotalScaleEvents,
+		Cost:               (*CostStatus)(st.Cost),
+		ObservedGeneration: st.ObservedGeneration,
+	}
+	for _, p := range st.PVCs {
+		pvc := PVCStatus{
+			Name:                    p.Name,
+			CurrentSize:             p.CurrentSize,
+			UsageBytes:              p.UsageBytes,
+			UsagePercent:            p.UsagePercent,
+			FreeBytes:               p.FreeBytes,
+			TriggeredBy:             p.TriggeredBy,
+			LastScaleTime:           p.LastScaleTime,
+			LastScaleSize:           p.LastScaleSize,
+			Anomaly:                 (*PVCAnomaly)(p.Anomaly),
+			Unhealthy:               (*PVCHealth)(p.Unhealthy),
+			AnomalyAcknowledgedTime: p.AnomalyAcknowledgedTime,
+		}
+		for _, e := range p.RecentExpansions {
+			pvc.RecentExpansions = append(pvc.RecentExpansions, PVCExpansion(e))
+		}
+		dst.Status.PVCs = append(dst.Status.PVCs, pvc)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+)
+
+// fullVolumeAutoscaler sets every field, so a field missed by the conversion
+// shows up as a round-trip difference.
+func fullVolumeAutoscaler() *VolumeAutoscaler {
+	q := func(s string) *resource.Quantity { v := resource.MustParse(s); return &v }
+	d := func(t time.Duration) *metav1.Duration { return &metav1.Duration{Duration: t} }
+	now := metav1.NewTime(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	return &VolumeAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "loki", Namespace: "monitoring", Generation: 4,
+			Labels: map[string]string{"volume-autoscaler.io/shard": "a"}},
+		Spec: VolumeAutoscalerSpec{
+			Target: VolumeAutoscalerTarget{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "loki"}},
+				OwnerRef: &VolumeAutoscalerOwnerRef{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "loki"},
+			},
+			Suspend:                   true,
+			ThresholdPercent:          75,
+			MinFreeBytes:              q("20Gi"),
+			ThresholdMode:             ThresholdModeAll,
+			MaxSize:                   resource.MustParse("200Gi"),
+			IncreasePercent:           25,
+			IncreaseMinimum:           q("5Gi"),
+			EmergencyThresholdPercent: 95,
+			EmergencyIncreasePercent:  60,
+			PollInterval:              d(time.Minute),
+			CooldownPeriod:            d(5 * time.Minute),
+			InodeThresholdPercent:     90,
+			PrometheusURL:             "http://prometheus:9090",
+			AnomalyDetection: &AnomalyDetection{MaxJumpPercent: 20, GrowthRateFactor: 4,
+				RateWindow: d(time.Hour), BaselineWindow: d(168 * time.Hour), MaxExpansionsPerDay: 3,
+				ProjectMaxSize: true, SuppressExpansion: true},
+		},
+		Status: VolumeAutoscalerStatus{
+			Conditions:   []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Polled", LastTransitionTime: now}},
+			LastPollTime: &now,
+			PVCs: []PVCStatus{{
+				Name: "data-loki-0", CurrentSize: resource.MustParse("60Gi"), UsageBytes: 50 << 30,
+				UsagePercent: 83, FreeBytes: 10 << 30, TriggeredBy: []string{"thresholdPercent", "minFreeBytes"},
+				LastScaleTime: &now, LastScaleSize: q("60Gi"),
+				RecentExpansions:        []PVCExpansion{{Time: now, From: resource.MustParse("50Gi"), To: resource.MustParse("60Gi")}},
+				Anomaly:                 &PVCAnomaly{Kind: "Jump", Message: "grew 25%", DetectedTime: now},
+				Unhealthy:               &PVCHealth{Source: "Longhorn", Reason: "Degraded", Message: "1 of 3 replicas rebuilding"},
+				AnomalyAcknowledgedTime: &now,
+			}},
+			TotalScaleEvents:   7,
+			Cost:               &CostStatus{Currency: "EUR", ProvisionedMonthly: "4.80", AddedLast30DaysMonthly: "0.80", UnpricedPVCs: 1},
+			ObservedGeneration: 4,
+		},
+	}
+}
+
+func TestConversion_RoundTrip(t *testing.T) {
+	src := fullVolumeAutoscaler()
+	var hub v1beta1.VolumeAutoscaler
+	if err := src.DeepCopy().ConvertTo(&hub); err != nil {
+		t.Fatalf("ConvertTo: %v", err)
+	}
+	var back VolumeAutoscaler
+	if err := back.ConvertFrom(&hub); err != nil {
+		t.Fatalf("ConvertFrom: %v", err)
+	}
+	if !equality.Semantic.DeepEqual(src, &back) {
+		t.Errorf("round trip changed the object:\nbefore: %+v\nafter:  %+v", src.Spec, back.Spec)
+	}
+}
+
+func TestConversion_GroupsSpec(t *testing.T) {
+	var hub v1beta1.VolumeAutoscaler
+	if err := fullVolumeAutoscaler().ConvertTo(&hub); err != nil {
+		t.Fatalf("ConvertTo: %v", err)
+	}
+	s := hub.Spec
+	if s.Trigger.ThresholdPercent != 75 || s.Trigger.Mode != v1beta1.ThresholdModeAll {
+		t.Errorf("trigger = %+v", s.Trigger)
+	}
+	if s.Growth.MinIncrement.String() != "5Gi" || s.Growth.CooldownPeriod.Duration != 5*time.Minute {
+		t.Errorf("growth = %+v", s.Growth)
+	}
+	if s.Limits.MaxSize.String() != "200Gi" {
+		t.Errorf("limits.maxSize = %s, want 200Gi", s.Limits.MaxSize.String())
+	}
+	if s.Source.Prometheus.URL != "http://prometheus:9090" || s.Source.PollInterval.Duration != time.Minute {
+		t.Errorf("source = %+v", s.Source)
+	}
+}
+
+func TestConversion_EmptyStatus(t *testing.T) {
+	src := &VolumeAutoscaler{Spec: VolumeAutoscalerSpec{MaxSize: resource.MustParse("10Gi")}}
+	var hub v1beta1.VolumeAutoscaler
+	if err := src.ConvertTo(&hub); err != nil {
+		t.Fatalf("ConvertTo: %v", err)
+	}
+	var back VolumeAutoscaler
+	if err := back.ConvertFrom(&hub); err != nil {
+		t.Fatalf("ConvertFrom: %v", err)
+	}
+	if back.Status.PVCs != nil || back.Spec.Target.OwnerRef != nil || back.Spec.AnomalyDetection != nil {
+		t.Errorf("empty fields became non-nil: %+v", back)
+	}
+}
+
+func TestConversion_V1beta1OnlyFieldsRoundTrip(t *testing.T) {
+	var hub v1beta1.VolumeAutoscaler
+	if err := fullVolumeAutoscaler().ConvertTo(&hub); err != nil {
+		t.Fatalf("ConvertTo: %v", err)
+	}
+	maxIncrement := resource.MustParse("100Gi")
+	hub.Spec.Trigger.Forecast = &v1beta1.ForecastSpec{Window: &metav1.Duration{Duration: 6 * time.Hour}}
+	hub.Spec.Growth.MaxIncrement = &maxIncrement
+	hub.Spec.Growth.Steps = []v1beta1.GrowthStep{{AboveSize: resource.MustParse("1Ti"), IncreasePercent: 10}}
+	hub.Spec.Limits.Budget = &v1beta1.BudgetSpec{MaxMonthlyCost: "250"}
+	hub.Spec.Source.Prometheus.Auth = &v1beta1.PrometheusAuth{BearerTokenSecretRef: &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "prometheus-token"}, Key: "token"}}
+	want := hub.DeepCopy()
+
+	var spoke VolumeAutoscaler
+	if err := spoke.ConvertFrom(&hub); err != nil {
+		t.Fatalf("ConvertFrom: %v", err)
+	}
+	if _, ok := spoke.Annotations[AnnotationV1beta1Spec]; !ok {
+		t.Fatalf("v1beta1-only fields not stashed: %v", spoke.Annotations)
+	}
+	if _, ok := hub.Annotations[AnnotationV1beta1Spec]; ok {
+		t.Errorf("ConvertFrom annotated the source object")
+	}
+
+	var back v1beta1.VolumeAutoscaler
+	if err := spoke.ConvertTo(&back); err != nil {
+		t.Fatalf("ConvertTo: %v", err)
+	}
+	if !equality.Semantic.DeepEqual(want, &back) {
+		t.Errorf("round trip changed the object:\nbefore: %+v\nafter:  %+v", want.Spec, back.Spec)
+	}
+}
+
+func TestConversion_NoStashWithoutV1beta1OnlyFields(t *testing.T) {
+	var hub v1beta1.VolumeAutoscaler
+	if err := fullVolumeAutoscaler().ConvertTo(&hub); err != nil {
+		t.Fatalf("ConvertTo: %v", err)
+	}
+	var spoke VolumeAutoscaler
+	if err := spoke.ConvertFrom(&hub); err != nil {
+		t.Fatalf("ConvertFrom: %v", err)
+	}
+	if _, ok := spoke.Annotations[AnnotationV1beta1Spec]; ok {
+		t.Errorf("unexpected %s annotation: %v", AnnotationV1beta1Spec, spoke.Annotations)
+	}
+}
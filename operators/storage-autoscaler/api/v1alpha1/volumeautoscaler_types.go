@@ -21,47 +21,173 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// VolumeAutoscalerTarget identifies the PVCs to scale.
+// VolumeAutoscalerOwnerRef identifies the object that owns the PVCs to scale.
+type VolumeAutoscalerOwnerRef struct {
+	// apiVersion of the owner, for example postgresql.cnpg.io/v1. Only the
+	// group is compared. When empty, any group matches.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// kind of the owner, for example Cluster or StatefulSet.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Kind string `json:"kind"`
+
+	// name of the owner in the CR's namespace.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+}
+
+// VolumeAutoscalerTarget identifies the PVCs to scale. Exactly one of
+// pvcName, selector and ownerRef must be set.
+// +kubebuilder:validation:XValidation:rule="[has(self.pvcName), has(self.selector), has(self.ownerRef)].filter(x, x).size() == 1",message="exactly one of pvcName, selector and ownerRef must be set"
 type VolumeAutoscalerTarget struct {
 	// pvcName targets a single PVC by name in the CR's namespace.
-	// Mutually exclusive with selector.
+	// Mutually exclusive with selector and ownerRef.
 	// +optional
 	PVCName string `json:"pvcName,omitempty"`
 
 	// selector matches multiple PVCs by labels in the CR's namespace.
-	// Mutually exclusive with pvcName.
+	// Mutually exclusive with pvcName and ownerRef. PVCs annotated
+	// volume-autoscaler.io/exclude: "true" are skipped.
 	// +optional
 	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// ownerRef matches PVCs owned by another object in the CR's namespace,
+	// such as a CloudNativePG Cluster. PVCs owned by a Pod that the object
+	// owns (generic ephemeral volumes) and, for a StatefulSet, PVCs created
+	// from its volumeClaimTemplates also match. Mutually exclusive with
+	// pvcName and selector.
+	// +optional
+	OwnerRef *VolumeAutoscalerOwnerRef `json:"ownerRef,omitempty"`
+}
+
+// AnomalyDetection configures detection of abnormal volume growth, such as a
+// runaway writer filling a PVC faster than it has ever grown before.
+type AnomalyDetection struct {
+	// maxJumpPercent flags a PVC whose usage grows by more than this percentage
+	// of its capacity between two consecutive polls. 0 disables the check.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxJumpPercent int32 `json:"maxJumpPercent,omitempty"`
+
+	// growthRateFactor flags a PVC whose growth rate over rateWindow is more
+	// than this multiple of its baseline rate over baselineWindow.
+	// 0 disables the check.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	GrowthRateFactor int32 `json:"growthRateFactor,omitempty"`
+
+	// rateWindow is the recent window the growth rate is measured over.
+	// +kubebuilder:default="1h"
+	// +optional
+	RateWindow *metav1.Duration `json:"rateWindow,omitempty"`
+
+	// baselineWindow is the historical window the baseline growth rate is
+	// measured over.
+	// +kubebuilder:default="168h"
+	// +optional
+	BaselineWindow *metav1.Duration `json:"baselineWindow,omitempty"`
+
+	// maxExpansionsPerDay flags a PVC expanded more than this many times
+	// within 24 hours. 0 disables the check.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxExpansionsPerDay int32 `json:"maxExpansionsPerDay,omitempty"`
+
+	// projectMaxSize flags a PVC expanded more than once within 24 hours
+	// whose capacity, if it keeps growing at that rate, reaches maxSize within
+	// the next 24 hours.
+	// +optional
+	ProjectMaxSize bool `json:"projectMaxSize,omitempty"`
+
+	// suppressExpansion stops expanding a PVC once an anomaly is detected,
+	// until it is acknowledged with the volume-autoscaler.io/anomaly-ack
+	// PVC annotation. Forced expansions are still honoured.
+	// +optional
+	SuppressExpansion bool `json:"suppressExpansion,omitempty"`
 }
 
+// ThresholdMode says how thresholdPercent and minFreeBytes combine.
+type ThresholdMode string
+
+const (
+	// ThresholdModeAny expands when any criterion fires.
+	ThresholdModeAny ThresholdMode = "any"
+	// ThresholdModeAll expands only when every configured criterion fires.
+	ThresholdModeAll ThresholdMode = "all"
+)
+
 // VolumeAutoscalerSpec defines the desired state of VolumeAutoscaler.
+// +kubebuilder:validation:XValidation:rule="!has(self.emergencyThresholdPercent) || self.emergencyThresholdPercent == 0 || !has(self.thresholdPercent) || self.emergencyThresholdPercent > self.thresholdPercent",message="emergencyThresholdPercent must be greater than thresholdPercent"
 type VolumeAutoscalerSpec struct {
 	// target identifies which PVCs to autoscale.
 	// +required
 	Target VolumeAutoscalerTarget `json:"target"`
 
+	// suspend pauses autoscaling without deleting the resource, so status
+	// history is kept. No metrics are queried and no PVCs are patched.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
 	// thresholdPercent is the usage percentage that triggers expansion.
-	// +kubebuilder:default=80
+	// Defaults to the controller config value (80 unless overridden).
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=99
 	// +optional
 	ThresholdPercent int32 `json:"thresholdPercent,omitempty"`
 
+	// minFreeBytes triggers expansion when free space drops below this
+	// amount. A percentage alone leaves hundreds of GiB idle on large volumes
+	// and almost nothing on small ones; this gives an absolute floor.
+	// Combined with thresholdPercent according to thresholdMode.
+	// +optional
+	MinFreeBytes *resource.Quantity `json:"minFreeBytes,omitempty"`
+
+	// thresholdMode combines thresholdPercent and minFreeBytes: "any" expands
+	// when either criterion fires, "all" only when both do. Ignored when
+	// minFreeBytes is unset.
+	// +kubebuilder:validation:Enum=any;all
+	// +kubebuilder:default=any
+	// +optional
+	ThresholdMode ThresholdMode `json:"thresholdMode,omitempty"`
+
 	// maxSize is the maximum size a PVC can be expanded to. Required safety cap.
+	// A PVC can override it with the volume-autoscaler.io/max-size annotation.
 	// +required
 	MaxSize resource.Quantity `json:"maxSize"`
 
 	// increasePercent is the percentage of current capacity to add on each expansion.
-	// +kubebuilder:default=20
+	// Defaults to the controller config value (20 unless overridden).
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=100
 	// +optional
 	IncreasePercent int32 `json:"increasePercent,omitempty"`
 
 	// increaseMinimum is the minimum amount to add per expansion (floor for small PVCs).
+	// Defaults to the controller config value (1Gi unless overridden).
 	// +optional
 	IncreaseMinimum *resource.Quantity `json:"increaseMinimum,omitempty"`
 
+	// emergencyThresholdPercent is a second, higher usage percentage at which the
+	// PVC is expanded even if the cooldown period has not elapsed. maxSize still applies.
+	// 0 means disabled.
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	EmergencyThresholdPercent int32 `json:"emergencyThresholdPercent,omitempty"`
+
+	// emergencyIncreasePercent is the percentage of current capacity to add on an
+	// emergency expansion. Should be larger than increasePercent.
+	// Defaults to the controller config value (50 unless overridden).
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=200
+	// +optional
+	EmergencyIncreasePercent int32 `json:"emergencyIncreasePercent,omitempty"`
+
 	// pollInterval is how often to check volume metrics.
 	// +kubebuilder:default="60s"
 	// +optional
@@ -81,9 +207,72 @@ type VolumeAutoscalerSpec struct {
 	InodeThresholdPercent int32 `json:"inodeThresholdPercent,omitempty"`
 
 	// prometheusURL is the Prometheus endpoint to query for volume metrics.
-	// +kubebuilder:default="http://prometheus.monitoring.svc.cluster.local:9090"
+	// Defaults to the controller config value.
 	// +optional
 	PrometheusURL string `json:"prometheusURL,omitempty"`
+
+	// anomalyDetection flags abnormal growth with AnomalousGrowth events.
+	// Disabled when unset.
+	// +optional
+	AnomalyDetection *AnomalyDetection `json:"anomalyDetection,omitempty"`
+}
+
+// PVCExpansion records one expansion applied to a PVC.
+type PVCExpansion struct {
+	// time is when the expansion was applied.
+	Time metav1.Time `json:"time"`
+
+	// from is the capacity before the expansion.
+	From resource.Quantity `json:"from"`
+
+	// to is the requested size after the expansion.
+	To resource.Quantity `json:"to"`
+}
+
+// CostStatus summarises the storage cost of the targeted PVCs, using the
+// StorageClass price table in the controller config. Amounts are per month.
+type CostStatus struct {
+	// currency is the unit of the amounts, as set in the controller config.
+	// +optional
+	Currency string `json:"currency,omitempty"`
+
+	// provisionedMonthly is the monthly cost of the PVCs' current capacity.
+	ProvisionedMonthly string `json:"provisionedMonthly"`
+
+	// addedLast30DaysMonthly is the monthly cost of the capacity added by
+	// autoscaling in the last 30 days.
+	AddedLast30DaysMonthly string `json:"addedLast30DaysMonthly"`
+
+	// unpricedPVCs counts targeted PVCs whose StorageClass has no price.
+	// +optional
+	UnpricedPVCs int32 `json:"unpricedPVCs,omitempty"`
+}
+
+// PVCAnomaly records abnormal growth detected on a PVC.
+type PVCAnomaly struct {
+	// kind is the check that fired: Jump, GrowthRate, ExpansionRate or
+	// MaxSizeProjected.
+	Kind string `json:"kind"`
+
+	// message describes the observation.
+	Message string `json:"message"`
+
+	// detectedTime is when the anomaly was first detected.
+	DetectedTime metav1.Time `json:"detectedTime"`
+}
+
+// PVCHealth records why a PVC's volume is considered unhealthy.
+type PVCHealth struct {
+	// source is the health source that reported it: kubelet,
+	// VolumeCondition or Longhorn.
+	Source string `json:"source"`
+
+	// reason is a short cause, e.g. VolumeConditionAbnormal or Degraded.
+	Reason string `json:"reason"`
+
+	// message is the detail given by the source.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // PVCStatus tracks the observed state of an individual PVC.
@@ -102,6 +291,17 @@ type PVCStatus struct {
 	// +optional
 	UsagePercent int32 `json:"usagePercent,omitempty"`
 
+	// freeBytes is the number of bytes currently free on the filesystem.
+	// +optional
+	FreeBytes int64 `json:"freeBytes,omitempty"`
+
+	// triggeredBy lists the criteria that called for an expansion at the last
+	// poll: thresholdPercent, minFreeBytes, emergencyThresholdPercent or
+	// expandNow. Empty while the PVC is below its thresholds.
+	// +optional
+	// +listType=atomic
+	TriggeredBy []string `json:"triggeredBy,omitempty"`
+
 	// lastScaleTime is when this PVC was last expanded.
 	// +optional
 	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
@@ -109,6 +309,25 @@ type PVCStatus struct {
 	// lastScaleSize is the size of the last expansion.
 	// +optional
 	LastScaleSize *resource.Quantity `json:"lastScaleSize,omitempty"`
+
+	// recentExpansions lists the expansions within the last 30 days, oldest first.
+	// +optional
+	// +kubebuilder:validation:MaxItems=200
+	RecentExpansions []PVCExpansion `json:"recentExpansions,omitempty"`
+
+	// anomaly is set while abnormal growth is flagged and not yet acknowledged.
+	// +optional
+	Anomaly *PVCAnomaly `json:"anomaly,omitempty"`
+
+	// unhealthy is set while the volume is reported unhealthy; it blocks
+	// expansion. Health is checked when an expansion is called for.
+	// +optional
+	Unhealthy *PVCHealth `json:"unhealthy,omitempty"`
+
+	// anomalyAcknowledgedTime is when the last anomaly was acknowledged.
+	// Detection is paused for 24 hours afterwards.
+	// +optional
+	AnomalyAcknowledgedTime *metav1.Time `json:"anomalyAcknowledgedTime,omitempty"`
 }
 
 // VolumeAutoscalerStatus defines the observed state of VolumeAutoscaler.
@@ -131,6 +350,11 @@ type VolumeAutoscalerStatus struct {
 	// +optional
 	TotalScaleEvents int32 `json:"totalScaleEvents,omitempty"`
 
+	// cost summarises provisioned and autoscaled storage cost. Only set when
+	// the controller config has a StorageClass price table.
+	// +optional
+	Cost *CostStatus `json:"cost,omitempty"`
+
 	// observedGeneration is the most recent generation observed.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
@@ -138,8 +362,10 @@ type VolumeAutoscalerStatus struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:deprecatedversion:warning="autoscaling.volume-autoscaler.io/v1alpha1 VolumeAutoscaler is deprecated; use v1beta1"
 // +kubebuilder:printcolumn:name="Threshold",type=integer,JSONPath=`.spec.thresholdPercent`,description="Usage threshold percentage"
 // +kubebuilder:printcolumn:name="MaxSize",type=string,JSONPath=`.spec.maxSize`,description="Maximum PVC size"
+// +kubebuilder:printcolumn:name="Suspended",type=boolean,JSONPath=`.spec.suspend`,description="Whether autoscaling is paused"
 // +kubebuilder:printcolumn:name="ScaleEvents",type=integer,JSONPath=`.status.totalScaleEvents`,description="Total scale events"
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
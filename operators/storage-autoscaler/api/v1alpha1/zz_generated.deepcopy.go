@@ -25,10 +25,104 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnomalyDetection) DeepCopyInto(out *AnomalyDetection) {
+	*out = *in
+	if in.RateWindow != nil {
+		in, out := &in.RateWindow, &out.RateWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.BaselineWindow != nil {
+		in, out := &in.BaselineWindow, &out.BaselineWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnomalyDetection.
+func (in *AnomalyDetection) DeepCopy() *AnomalyDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(AnomalyDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostStatus) DeepCopyInto(out *CostStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostStatus.
+func (in *CostStatus) DeepCopy() *CostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCAnomaly) DeepCopyInto(out *PVCAnomaly) {
+	*out = *in
+	in.DetectedTime.DeepCopyInto(&out.DetectedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCAnomaly.
+func (in *PVCAnomaly) DeepCopy() *PVCAnomaly {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCAnomaly)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCExpansion) DeepCopyInto(out *PVCExpansion) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	out.From = in.From.DeepCopy()
+	out.To = in.To.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCExpansion.
+func (in *PVCExpansion) DeepCopy() *PVCExpansion {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCExpansion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCHealth) DeepCopyInto(out *PVCHealth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCHealth.
+func (in *PVCHealth) DeepCopy() *PVCHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PVCStatus) DeepCopyInto(out *PVCStatus) {
 	*out = *in
 	out.CurrentSize = in.CurrentSize.DeepCopy()
+	if in.TriggeredBy != nil {
+		in, out := &in.TriggeredBy, &out.TriggeredBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.LastScaleTime != nil {
 		in, out := &in.LastScaleTime, &out.LastScaleTime
 		*out = (*in).DeepCopy()
@@ -38,6 +132,27 @@ func (in *PVCStatus) DeepCopyInto(out *PVCStatus) {
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.RecentExpansions != nil {
+		in, out := &in.RecentExpansions, &out.RecentExpansions
+		*out = make([]PVCExpansion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Anomaly != nil {
+		in, out := &in.Anomaly, &out.Anomaly
+		*out = new(PVCAnomaly)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Unhealthy != nil {
+		in, out := &in.Unhealthy, &out.Unhealthy
+		*out = new(PVCHealth)
+		**out = **in
+	}
+	if in.AnomalyAcknowledgedTime != nil {
+		in, out := &in.AnomalyAcknowledgedTime, &out.AnomalyAcknowledgedTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCStatus.
@@ -109,10 +224,30 @@ func (in *VolumeAutoscalerList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeAutoscalerOwnerRef) DeepCopyInto(out *VolumeAutoscalerOwnerRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeAutoscalerOwnerRef.
+func (in *VolumeAutoscalerOwnerRef) DeepCopy() *VolumeAutoscalerOwnerRef {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeAutoscalerOwnerRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeAutoscalerSpec) DeepCopyInto(out *VolumeAutoscalerSpec) {
 	*out = *in
 	in.Target.DeepCopyInto(&out.Target)
+	if in.MinFreeBytes != nil {
+		in, out := &in.MinFreeBytes, &out.MinFreeBytes
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 	out.MaxSize = in.MaxSize.DeepCopy()
 	if in.IncreaseMinimum != nil {
 		in, out := &in.IncreaseMinimum, &out.IncreaseMinimum
@@ -129,6 +264,11 @@ func (in *VolumeAutoscalerSpec) DeepCopyInto(out *VolumeAutoscalerSpec) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.AnomalyDetection != nil {
+		in, out := &in.AnomalyDetection, &out.AnomalyDetection
+		*out = new(AnomalyDetection)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeAutoscalerSpec.
@@ -162,6 +302,11 @@ func (in *VolumeAutoscalerStatus) DeepCopyInto(out *VolumeAutoscalerStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(CostStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeAutoscalerStatus.
@@ -182,6 +327,11 @@ func (in *VolumeAutoscalerTarget) DeepCopyInto(out *VolumeAutoscalerTarget) {
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OwnerRef != nil {
+		in, out := &in.OwnerRef, &out.OwnerRef
+		*out = new(VolumeAutoscalerOwnerRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeAutoscalerTarget.
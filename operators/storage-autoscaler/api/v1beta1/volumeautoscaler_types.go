@@ -0,0 +1,536 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VolumeAutoscalerOwnerRef identifies the object that owns the PVCs to scale.
+type VolumeAutoscalerOwnerRef struct {
+	// apiVersion of the owner, for example postgresql.cnpg.io/v1. Only the
+	// group is compared. When empty, any group matches.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// kind of the owner, for example Cluster or StatefulSet.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Kind string `json:"kind"`
+
+	// name of the owner in the CR's namespace.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+}
+
+// VolumeAutoscalerTarget identifies the PVCs to scale. Exactly one of
+// pvcName, selector and ownerRef must be set.
+// +kubebuilder:validation:XValidation:rule="[has(self.pvcName), has(self.selector), has(self.ownerRef)].filter(x, x).size() == 1",message="exactly one of pvcName, selector and ownerRef must be set"
+type VolumeAutoscalerTarget struct {
+	// pvcName targets a single PVC by name in the CR's namespace.
+	// Mutually exclusive with selector and ownerRef.
+	// +optional
+	PVCName string `json:"pvcName,omitempty"`
+
+	// selector matches multiple PVCs by labels in the CR's namespace.
+	// Mutually exclusive with pvcName and ownerRef. PVCs annotated
+	// volume-autoscaler.io/exclude: "true" are skipped.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// ownerRef matches PVCs owned by another object in the CR's namespace,
+	// such as a CloudNativePG Cluster. PVCs owned by a Pod that the object
+	// owns (generic ephemeral volumes) and, for a StatefulSet, PVCs created
+	// from its volumeClaimTemplates also match. Mutually exclusive with
+	// pvcName and selector.
+	// +optional
+	OwnerRef *VolumeAutoscalerOwnerRef `json:"ownerRef,omitempty"`
+}
+
+// AnomalyDetection configures detection of abnormal volume growth, such as a
+// runaway writer filling a PVC faster than it has ever grown before.
+type AnomalyDetection struct {
+	// maxJumpPercent flags a PVC whose usage grows by more than this percentage
+	// of its capacity between two consecutive polls. 0 disables the check.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxJumpPercent int32 `json:"maxJumpPercent,omitempty"`
+
+	// growthRateFactor flags a PVC whose growth rate over rateWindow is more
+	// than this multiple of its baseline rate over baselineWindow.
+	// 0 disables the check.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	GrowthRateFactor int32 `json:"growthRateFactor,omitempty"`
+
+	// rateWindow is the recent window the growth rate is measured over.
+	// +kubebuilder:default="1h"
+	// +optional
+	RateWindow *metav1.Duration `json:"rateWindow,omitempty"`
+
+	// baselineWindow is the historical window the baseline growth rate is
+	// measured over.
+	// +kubebuilder:default="168h"
+	// +optional
+	BaselineWindow *metav1.Duration `json:"baselineWindow,omitempty"`
+
+	// maxExpansionsPerDay flags a PVC expanded more than this many times
+	// within 24 hours. 0 disables the check.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxExpansionsPerDay int32 `json:"maxExpansionsPerDay,omitempty"`
+
+	// projectMaxSize flags a PVC expanded more than once within 24 hours
+	// whose capacity, if it keeps growing at that rate, reaches maxSize within
+	// the next 24 hours.
+	// +optional
+	ProjectMaxSize bool `json:"projectMaxSize,omitempty"`
+
+	// suppressExpansion stops expanding a PVC once an anomaly is detected,
+	// until it is acknowledged with the volume-autoscaler.io/anomaly-ack
+	// PVC annotation. Forced expansions are still honoured.
+	// +optional
+	SuppressExpansion bool `json:"suppressExpansion,omitempty"`
+}
+
+// ThresholdMode says how thresholdPercent and minFreeBytes combine.
+type ThresholdMode string
+
+const (
+	// ThresholdModeAny expands when any criterion fires.
+	ThresholdModeAny ThresholdMode = "any"
+	// ThresholdModeAll expands only when every configured criterion fires.
+	ThresholdModeAll ThresholdMode = "all"
+)
+
+// ForecastSpec configures expansion ahead of the threshold, from the growth
+// trend of a PVC.
+type ForecastSpec struct {
+	// window is how much usage history the trend is fitted over.
+	// +kubebuilder:default="6h"
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// lookahead expands a PVC whose usage is projected to cross
+	// thresholdPercent within this duration.
+	// +kubebuilder:default="1h"
+	// +optional
+	Lookahead *metav1.Duration `json:"lookahead,omitempty"`
+}
+
+// TriggerSpec decides when a PVC is expanded.
+// +kubebuilder:validation:XValidation:rule="!has(self.forecast)",message="forecast is not implemented yet"
+// +kubebuilder:validation:XValidation:rule="!has(self.emergencyThresholdPercent) || self.emergencyThresholdPercent == 0 || !has(self.thresholdPercent) || self.emergencyThresholdPercent > self.thresholdPercent",message="emergencyThresholdPercent must be greater than thresholdPercent"
+type TriggerSpec struct {
+	// thresholdPercent is the usage percentage that triggers expansion.
+	// Defaults to the controller config value (80 unless overridden).
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	ThresholdPercent int32 `json:"thresholdPercent,omitempty"`
+
+	// minFreeBytes triggers expansion when free space drops below this
+	// amount. A percentage alone leaves hundreds of GiB idle on large volumes
+	// and almost nothing on small ones; this gives an absolute floor.
+	// Combined with thresholdPercent according to mode.
+	// +optional
+	MinFreeBytes *resource.Quantity `json:"minFreeBytes,omitempty"`
+
+	// mode combines thresholdPercent and minFreeBytes: "any" expands when
+	// either criterion fires, "all" only when both do. Ignored when
+	// minFreeBytes is unset.
+	// +kubebuilder:validation:Enum=any;all
+	// +kubebuilder:default=any
+	// +optional
+	Mode ThresholdMode `json:"mode,omitempty"`
+
+	// emergencyThresholdPercent is a second, higher usage percentage at which the
+	// PVC is expanded even if the cooldown period has not elapsed. maxSize still applies.
+	// 0 means disabled.
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	EmergencyThresholdPercent int32 `json:"emergencyThresholdPercent,omitempty"`
+
+	// inodeThresholdPercent triggers expansion when inode usage exceeds this percentage.
+	// 0 means disabled.
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	InodeThresholdPercent int32 `json:"inodeThresholdPercent,omitempty"`
+
+	// forecast expands ahead of the threshold from the PVC's growth trend.
+	// Reserved: rejected until the controller acts on it.
+	// +optional
+	Forecast *ForecastSpec `json:"forecast,omitempty"`
+}
+
+// GrowthStep sets the increase percentage for PVCs of at least a given size.
+type GrowthStep struct {
+	// aboveSize is the smallest current capacity the step applies to.
+	// +required
+	AboveSize resource.Quantity `json:"aboveSize"`
+
+	// increasePercent replaces growth.increasePercent for PVCs in this step.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +required
+	IncreasePercent int32 `json:"increasePercent"`
+}
+
+// GrowthSpec decides how much a PVC grows per expansion and how often.
+type GrowthSpec struct {
+	// increasePercent is the percentage of current capacity to add on each expansion.
+	// Defaults to the controller config value (20 unless overridden).
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	IncreasePercent int32 `json:"increasePercent,omitempty"`
+
+	// minIncrement is the minimum amount to add per expansion (floor for small PVCs).
+	// Defaults to the controller config value (1Gi unless overridden).
+	// +optional
+	MinIncrement *resource.Quantity `json:"minIncrement,omitempty"`
+
+	// maxIncrement is the most to add per expansion, emergency expansions
+	// included, so a percentage does not add terabytes to a large PVC.
+	// It takes precedence over minIncrement.
+	// +optional
+	MaxIncrement *resource.Quantity `json:"maxIncrement,omitempty"`
+
+	// steps picks the increase percentage of a normal expansion by PVC size:
+	// the step with the largest aboveSize not exceeding the current capacity
+	// applies. Below every step, increasePercent applies.
+	// +kubebuilder:validation:MaxItems=16
+	// +listType=atomic
+	// +optional
+	Steps []GrowthStep `json:"steps,omitempty"`
+
+	// emergencyIncreasePercent is the percentage of current capacity to add on an
+	// emergency expansion. Should be larger than increasePercent.
+	// Defaults to the controller config value (50 unless overridden).
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=200
+	// +optional
+	EmergencyIncreasePercent int32 `json:"emergencyIncreasePercent,omitempty"`
+
+	// cooldownPeriod is the minimum wait time between consecutive expansions of the same PVC.
+	// +kubebuilder:default="5m"
+	// +optional
+	CooldownPeriod *metav1.Duration `json:"cooldownPeriod,omitempty"`
+}
+
+// BudgetSpec bounds the storage all targeted PVCs may add up to.
+type BudgetSpec struct {
+	// maxTotalSize is the most capacity the targeted PVCs may have together.
+	// +optional
+	MaxTotalSize *resource.Quantity `json:"maxTotalSize,omitempty"`
+
+	// maxMonthlyCost is the most the targeted PVCs may cost per month, in the
+	// currency of the controller config's StorageClass price table.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// +optional
+	MaxMonthlyCost string `json:"maxMonthlyCost,omitempty"`
+}
+
+// LimitsSpec bounds how far PVCs are grown.
+// +kubebuilder:validation:XValidation:rule="!has(self.budget)",message="budget is not implemented yet"
+type LimitsSpec struct {
+	// maxSize is the maximum size a PVC can be expanded to. Required safety cap.
+	// A PVC can override it with the volume-autoscaler.io/max-size annotation.
+	// +required
+	MaxSize resource.Quantity `json:"maxSize"`
+
+	// budget bounds the total size and cost of the targeted PVCs.
+	// Reserved: rejected until the controller acts on it.
+	// +optional
+	Budget *BudgetSpec `json:"budget,omitempty"`
+}
+
+// PrometheusAuth says how to authenticate to the Prometheus API.
+type PrometheusAuth struct {
+	// bearerTokenSecretRef selects a key of a Secret in the CR's namespace
+	// holding a bearer token.
+	// +optional
+	BearerTokenSecretRef *corev1.SecretKeySelector `json:"bearerTokenSecretRef,omitempty"`
+}
+
+// PrometheusSource reads volume metrics from a Prometheus-compatible API.
+// +kubebuilder:validation:XValidation:rule="!has(self.auth)",message="auth is not implemented yet"
+type PrometheusSource struct {
+	// url is the Prometheus endpoint to query for volume metrics.
+	// Defaults to the controller config value.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// auth authenticates the queries.
+	// Reserved: rejected until the controller acts on it.
+	// +optional
+	Auth *PrometheusAuth `json:"auth,omitempty"`
+}
+
+// MetricsBackend names the system volume metrics are read from.
+type MetricsBackend string
+
+const (
+	// MetricsBackendPrometheus reads kubelet volume stats from a
+	// Prometheus-compatible API.
+	MetricsBackendPrometheus MetricsBackend = "prometheus"
+)
+
+// SourceSpec says where volume metrics come from and how often they are read.
+type SourceSpec struct {
+	// backend selects the metrics backend. Only prometheus is supported.
+	// +kubebuilder:validation:Enum=prometheus
+	// +kubebuilder:default=prometheus
+	// +optional
+	Backend MetricsBackend `json:"backend,omitempty"`
+
+	// prometheus configures the Prometheus metrics backend.
+	// +optional
+	Prometheus PrometheusSource `json:"prometheus,omitzero"`
+
+	// pollInterval is how often to check volume metrics.
+	// +kubebuilder:default="60s"
+	// +optional
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+}
+
+// VolumeAutoscalerSpec defines the desired state of VolumeAutoscaler.
+type VolumeAutoscalerSpec struct {
+	// target identifies which PVCs to autoscale.
+	// +required
+	Target VolumeAutoscalerTarget `json:"target"`
+
+	// suspend pauses autoscaling without deleting the resource, so status
+	// history is kept. No metrics are queried and no PVCs are patched.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// trigger decides when a PVC is expanded.
+	// +kubebuilder:default={}
+	// +optional
+	Trigger TriggerSpec `json:"trigger,omitzero"`
+
+	// growth decides how much a PVC grows per expansion and how often.
+	// +kubebuilder:default={}
+	// +optional
+	Growth GrowthSpec `json:"growth,omitzero"`
+
+	// limits bounds how far PVCs are grown.
+	// +required
+	Limits LimitsSpec `json:"limits"`
+
+	// source says where volume metrics come from.
+	// +kubebuilder:default={}
+	// +optional
+	Source SourceSpec `json:"source,omitzero"`
+
+	// anomalyDetection flags abnormal growth with AnomalousGrowth events.
+	// Disabled when unset.
+	// +optional
+	AnomalyDetection *AnomalyDetection `json:"anomalyDetection,omitempty"`
+}
+
+// PVCExpansion records one expansion applied to a PVC.
+type PVCExpansion struct {
+	// time is when the expansion was applied.
+	Time metav1.Time `json:"time"`
+
+	// from is the capacity before the expansion.
+	From resource.Quantity `json:"from"`
+
+	// to is the requested size after the expansion.
+	To resource.Quantity `json:"to"`
+}
+
+// CostStatus summarises the storage cost of the targeted PVCs, using the
+// StorageClass price table in the controller config. Amounts are per month.
+type CostStatus struct {
+	// currency is the unit of the amounts, as set in the controller config.
+	// +optional
+	Currency string `json:"currency,omitempty"`
+
+	// provisionedMonthly is the monthly cost of the PVCs' current capacity.
+	ProvisionedMonthly string `json:"provisionedMonthly"`
+
+	// addedLast30DaysMonthly is the monthly cost of the capacity added by
+	// autoscaling in the last 30 days.
+	AddedLast30DaysMonthly string `json:"addedLast30DaysMonthly"`
+
+	// unpricedPVCs counts targeted PVCs whose StorageClass has no price.
+	// +optional
+	UnpricedPVCs int32 `json:"unpricedPVCs,omitempty"`
+}
+
+// PVCAnomaly records abnormal growth detected on a PVC.
+type PVCAnomaly struct {
+	// kind is the check that fired: Jump, GrowthRate, ExpansionRate or
+	// MaxSizeProjected.
+	Kind string `json:"kind"`
+
+	// message describes the observation.
+	Message string `json:"message"`
+
+	// detectedTime is when the anomaly was first detected.
+	DetectedTime metav1.Time `json:"detectedTime"`
+}
+
+// PVCHealth records why a PVC's volume is considered unhealthy.
+type PVCHealth struct {
+	// source is the health source that reported it: kubelet,
+	// VolumeCondition or Longhorn.
+	Source string `json:"source"`
+
+	// reason is a short cause, e.g. VolumeConditionAbnormal or Degraded.
+	Reason string `json:"reason"`
+
+	// message is the detail given by the source.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PVCStatus tracks the observed state of an individual PVC.
+type PVCStatus struct {
+	// name is the PVC name.
+	Name string `json:"name"`
+
+	// currentSize is the current storage capacity of the PVC.
+	CurrentSize resource.Quantity `json:"currentSize"`
+
+	// usageBytes is the number of bytes currently used.
+	// +optional
+	UsageBytes int64 `json:"usageBytes,omitempty"`
+
+	// usagePercent is the current usage as a percentage of capacity.
+	// +optional
+	UsagePercent int32 `json:"usagePercent,omitempty"`
+
+	// freeBytes is the number of bytes currently free on the filesystem.
+	// +optional
+	FreeBytes int64 `json:"freeBytes,omitempty"`
+
+	// triggeredBy lists the criteria that called for an expansion at the last
+	// poll: thresholdPercent, minFreeBytes, emergencyThresholdPercent or
+	// expandNow. Empty while the PVC is below its thresholds.
+	// +optional
+	// +listType=atomic
+	TriggeredBy []string `json:"triggeredBy,omitempty"`
+
+	// lastScaleTime is when this PVC was last expanded.
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+
+	// lastScaleSize is the size of the last expansion.
+	// +optional
+	LastScaleSize *resource.Quantity `json:"lastScaleSize,omitempty"`
+
+	// recentExpansions lists the expansions within the last 30 days, oldest first.
+	// +optional
+	// +kubebuilder:validation:MaxItems=200
+	RecentExpansions []PVCExpansion `json:"recentExpansions,omitempty"`
+
+	// anomaly is set while abnormal growth is flagged and not yet acknowledged.
+	// +optional
+	Anomaly *PVCAnomaly `json:"anomaly,omitempty"`
+
+	// unhealthy is set while the volume is reported unhealthy; it blocks
+	// expansion. Health is checked when an expansion is called for.
+	// +optional
+	Unhealthy *PVCHealth `json:"unhealthy,omitempty"`
+
+	// anomalyAcknowledgedTime is when the last anomaly was acknowledged.
+	// Detection is paused for 24 hours afterwards.
+	// +optional
+	AnomalyAcknowledgedTime *metav1.Time `json:"anomalyAcknowledgedTime,omitempty"`
+}
+
+// VolumeAutoscalerStatus defines the observed state of VolumeAutoscaler.
+type VolumeAutoscalerStatus struct {
+	// conditions represent the current state of the VolumeAutoscaler resource.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// lastPollTime is the timestamp of the last metrics check.
+	// +optional
+	LastPollTime *metav1.Time `json:"lastPollTime,omitempty"`
+
+	// pvcs contains per-PVC status information.
+	// +optional
+	PVCs []PVCStatus `json:"pvcs,omitempty"`
+
+	// totalScaleEvents is the cumulative number of PVC expansions performed.
+	// +optional
+	TotalScaleEvents int32 `json:"totalScaleEvents,omitempty"`
+
+	// cost summarises provisioned and autoscaled storage cost. Only set when
+	// the controller config has a StorageClass price table.
+	// +optional
+	Cost *CostStatus `json:"cost,omitempty"`
+
+	// observedGeneration is the most recent generation observed.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Threshold",type=integer,JSONPath=`.spec.trigger.thresholdPercent`,description="Usage threshold percentage"
+// +kubebuilder:printcolumn:name="MaxSize",type=string,JSONPath=`.spec.limits.maxSize`,description="Maximum PVC size"
+// +kubebuilder:printcolumn:name="Suspended",type=boolean,JSONPath=`.spec.suspend`,description="Whether autoscaling is paused"
+// +kubebuilder:printcolumn:name="ScaleEvents",type=integer,JSONPath=`.status.totalScaleEvents`,description="Total scale events"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// VolumeAutoscaler is the Schema for the volumeautoscalers API. It is the
+// storage version and the conversion hub.
+type VolumeAutoscaler struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of VolumeAutoscaler.
+	// +required
+	Spec VolumeAutoscalerSpec `json:"spec"`
+
+	// status defines the observed state of VolumeAutoscaler.
+	// +optional
+	Status VolumeAutoscalerStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// VolumeAutoscalerList contains a list of VolumeAutoscaler.
+type VolumeAutoscalerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []VolumeAutoscaler `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VolumeAutoscaler{}, &VolumeAutoscalerList{})
+}
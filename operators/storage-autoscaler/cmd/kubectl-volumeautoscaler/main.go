@@ -0,0 +1,395 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command kubectl-volumeautoscaler is a kubectl plugin for inspecting and
+// operating VolumeAutoscalers. Install it on PATH and run
+// `kubectl volumeautoscaler <command>`.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/config"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/controller"
+)
+
+const usage = `Inspect and operate VolumeAutoscalers.
+
+Usage:
+  kubectl volumeautoscaler <command> [flags]
+
+Commands:
+  status [name]            Show per-PVC usage, headroom and cooldown
+  simulate <name>          Show what the controller would do on its next poll
+  expand-now <name>        Request a one-shot expansion, ignoring threshold and cooldown
+  ack <name>               Acknowledge growth anomalies so suppressed expansions resume
+  pause <name>             Suspend a VolumeAutoscaler
+  resume <name>            Resume a suspended VolumeAutoscaler
+
+Run 'kubectl volumeautoscaler <command> -h' for command flags.
+`
+
+// options holds the flags shared by every command.
+type options struct {
+	kubeconfig    string
+	kubeContext   string
+	namespace     string
+	allNamespaces bool
+	pvc           string
+	usage         int
+	config        string
+}
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(out, usage)
+		return nil
+	}
+	cmd, args := args[0], args[1:]
+
+	var opts options
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.StringVar(&opts.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file.")
+	fs.StringVar(&opts.kubeContext, "context", "", "Kubeconfig context to use.")
+	fs.StringVar(&opts.namespace, "namespace", "", "Namespace of the VolumeAutoscaler. Defaults to the kubeconfig namespace.")
+	fs.StringVar(&opts.namespace, "n", "", "Shorthand for --namespace.")
+	switch cmd {
+	case "status":
+		fs.BoolVar(&opts.allNamespaces, "all-namespaces", false, "List VolumeAutoscalers in all namespaces.")
+		fs.BoolVar(&opts.allNamespaces, "A", false, "Shorthand for --all-namespaces.")
+		fs.StringVar(&opts.config, "config", "", "ControllerConfig file with the manager's defaults.")
+	case "simulate":
+		fs.StringVar(&opts.pvc, "pvc", "", "Only simulate this PVC.")
+		fs.IntVar(&opts.usage, "usage", -1, "Usage percentage to simulate instead of the last observed value.")
+		fs.StringVar(&opts.config, "config", "", "ControllerConfig file with the manager's defaults.")
+	case "expand-now":
+		fs.StringVar(&opts.pvc, "pvc", "", "Only expand this PVC. Defaults to every PVC the VolumeAutoscaler targets.")
+	case "ack":
+		fs.StringVar(&opts.pvc, "pvc", "", "Only acknowledge this PVC. Defaults to every PVC with an anomaly.")
+	case "pause", "resume":
+	default:
+		return fmt.Errorf("unknown command %q, run 'kubectl volumeautoscaler help'", cmd)
+	}
+	// The name may come before, after or between the flags; the flag package
+	// stops at the first positional argument, so parse the rest again
+	var name string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if args = fs.Args(); len(args) == 0 {
+			break
+		}
+		if name != "" {
+			return fmt.Errorf("unexpected argument %q", args[0])
+		}
+		name, args = args[0], args[1:]
+	}
+	if name == "" && cmd != "status" {
+		return fmt.Errorf("%s requires a VolumeAutoscaler name", cmd)
+	}
+
+	c, namespace, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	if opts.namespace == "" {
+		opts.namespace = namespace
+	}
+
+	switch cmd {
+	case "status":
+		return status(ctx, c, opts, name, out)
+	case "simulate":
+		return simulate(ctx, c, opts, name, out)
+	case "expand-now":
+		return expandNow(ctx, c, opts, name, out)
+	case "ack":
+		return acknowledge(ctx, c, opts, name, out)
+	case "pause":
+		return setSuspended(ctx, c, opts, name, true, out)
+	default:
+		return setSuspended(ctx, c, opts, name, false, out)
+	}
+}
+
+// newClient is swapped out by tests.
+var newClient = kubeconfigClient
+
+// kubeconfigClient builds a client from the kubeconfig and returns it together
+// with the kubeconfig's default namespace.
+func kubeconfigClient(opts options) (client.Client, string, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = opts.kubeconfig
+	cc := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules,
+		&clientcmd.ConfigOverrides{CurrentContext: opts.kubeContext})
+	restConfig, err := cc.ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	namespace, _, err := cc.Namespace()
+	if err != nil {
+		return nil, "", fmt.Errorf("resolving namespace: %w", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, "", err
+	}
+	if err := autoscalingv1beta1.AddToScheme(scheme); err != nil {
+		return nil, "", err
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, "", fmt.Errorf("creating client: %w", err)
+	}
+	return c, namespace, nil
+}
+
+// loadConfig reads the ControllerConfig named by --config, or returns the
+// built-in defaults.
+func loadConfig(opts options) (*config.Config, error) {
+	if opts.config == "" {
+		return config.Default(), nil
+	}
+	return config.Load(opts.config)
+}
+
+// status prints one row per tracked PVC.
+func status(ctx context.Context, c client.Client, opts options, name string, out io.Writer) error {
+	cfg, err := loadConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	var vas []autoscalingv1beta1.VolumeAutoscaler
+	if name != "" {
+		var va autoscalingv1beta1.VolumeAutoscaler
+		if err := c.Get(ctx, types.NamespacedName{Namespace: opts.namespace, Name: name}, &va); err != nil {
+			return err
+		}
+		vas = append(vas, va)
+	} else {
+		var list autoscalingv1beta1.VolumeAutoscalerList
+		var listOpts []client.ListOption
+		if !opts.allNamespaces {
+			listOpts = append(listOpts, client.InNamespace(opts.namespace))
+		}
+		if err := c.List(ctx, &list, listOpts...); err != nil {
+			return err
+		}
+		vas = list.Items
+	}
+
+	return printStatus(out, vas, cfg.ThresholdPercent, time.Now())
+}
+
+// printStatus renders the status table. defaultThreshold is shown for
+// VolumeAutoscalers that leave thresholdPercent unset.
+func printStatus(out io.Writer, vas []autoscalingv1beta1.VolumeAutoscaler, defaultThreshold int32, now time.Time) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tNAME\tPVC\tSIZE\tUSAGE\tTHRESHOLD\tHEADROOM\tCOOLDOWN\tLAST EXPANSION")
+	for i := range vas {
+		va := &vas[i]
+		threshold := va.Spec.Trigger.ThresholdPercent
+		if threshold == 0 {
+			threshold = defaultThreshold
+		}
+		cooldown := controller.CooldownFor(va)
+		name := va.Name
+		if controller.Suspended(va) {
+			name += " (paused)"
+		}
+		if len(va.Status.PVCs) == 0 {
+			fmt.Fprintf(tw, "%s\t%s\t<none>\t\t\t%d%%\t\t\t\n", va.Namespace, name, threshold)
+			continue
+		}
+		for _, p := range va.Status.PVCs {
+			remaining := "-"
+			last := "never"
+			if p.LastScaleTime != nil {
+				if left := p.LastScaleTime.Add(cooldown).Sub(now); left > 0 {
+					remaining = left.Round(time.Second).String()
+				}
+				last = fmt.Sprintf("%s ago", now.Sub(p.LastScaleTime.Time).Round(time.Second))
+				if p.LastScaleSize != nil {
+					last += " (" + p.LastScaleSize.String() + ")"
+				}
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d%%\t%d%%\t%d%%\t%s\t%s\n",
+				va.Namespace, name, p.Name, p.CurrentSize.String(), p.UsagePercent,
+				threshold, threshold-p.UsagePercent, remaining, last)
+		}
+	}
+	return tw.Flush()
+}
+
+// simulate runs the controller's expansion plan against the live PVCs.
+func simulate(ctx context.Context, c client.Client, opts options, name string, out io.Writer) error {
+	cfg, err := loadConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	var va autoscalingv1beta1.VolumeAutoscaler
+	if err := c.Get(ctx, types.NamespacedName{Namespace: opts.namespace, Name: name}, &va); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "PVC\tSIZE\tUSAGE\tTHRESHOLD\tACTION\tNEW SIZE\tREASON")
+	for _, st := range va.Status.PVCs {
+		if opts.pvc != "" && st.Name != opts.pvc {
+			continue
+		}
+		var pvc corev1.PersistentVolumeClaim
+		if err := c.Get(ctx, types.NamespacedName{Namespace: va.Namespace, Name: st.Name}, &pvc); err != nil {
+			return err
+		}
+		usagePercent := st.UsagePercent
+		if opts.usage >= 0 {
+			usagePercent = int32(opts.usage)
+		}
+
+		plan := controller.PlanExpansion(ctx, c, cfg, clock.RealClock{}, &va, &pvc, st, usagePercent)
+		action, newSize, reason := "none", "-", "below threshold"
+		switch {
+		case plan.Blocked != "":
+			action, reason = "blocked", plan.Blocked
+		case plan.NewSize != nil:
+			action, newSize = "expand", plan.NewSize.String()
+			reason = fmt.Sprintf("%s (%s)", plan.Mode, strings.Join(plan.TriggeredBy, ", "))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d%%\t%d%%\t%s\t%s\t%s\n",
+			plan.PVC, plan.CurrentSize.String(), plan.UsagePercent, plan.ThresholdPercent, action, newSize, reason)
+	}
+	return tw.Flush()
+}
+
+// expandNow annotates the targeted PVCs so the controller force-expands them.
+func expandNow(ctx context.Context, c client.Client, opts options, name string, out io.Writer) error {
+	var va autoscalingv1beta1.VolumeAutoscaler
+	if err := c.Get(ctx, types.NamespacedName{Namespace: opts.namespace, Name: name}, &va); err != nil {
+		return err
+	}
+
+	names := []string{opts.pvc}
+	if opts.pvc == "" {
+		names = names[:0]
+		for _, p := range va.Status.PVCs {
+			names = append(names, p.Name)
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("VolumeAutoscaler %s/%s tracks no PVCs yet, use --pvc", va.Namespace, va.Name)
+		}
+	}
+
+	stamp := time.Now().UTC().Format(time.RFC3339)
+	for _, n := range names {
+		var pvc corev1.PersistentVolumeClaim
+		if err := c.Get(ctx, types.NamespacedName{Namespace: va.Namespace, Name: n}, &pvc); err != nil {
+			return err
+		}
+		patch := client.MergeFrom(pvc.DeepCopy())
+		if pvc.Annotations == nil {
+			pvc.Annotations = map[string]string{}
+		}
+		pvc.Annotations[controller.AnnotationExpandNow] = stamp
+		if err := c.Patch(ctx, &pvc, patch); err != nil {
+			return fmt.Errorf("annotating PVC %s: %w", n, err)
+		}
+		fmt.Fprintf(out, "persistentvolumeclaim/%s expansion requested\n", n)
+	}
+	fmt.Fprintln(out, "The controller expands on its next poll.")
+	return nil
+}
+
+// acknowledge annotates PVCs with an unacknowledged anomaly so the controller
+// clears it on its next poll.
+func acknowledge(ctx context.Context, c client.Client, opts options, name string, out io.Writer) error {
+	var va autoscalingv1beta1.VolumeAutoscaler
+	if err := c.Get(ctx, types.NamespacedName{Namespace: opts.namespace, Name: name}, &va); err != nil {
+		return err
+	}
+
+	stamp := time.Now().UTC().Format(time.RFC3339)
+	acked := 0
+	for _, st := range va.Status.PVCs {
+		if st.Anomaly == nil || (opts.pvc != "" && st.Name != opts.pvc) {
+			continue
+		}
+		var pvc corev1.PersistentVolumeClaim
+		if err := c.Get(ctx, types.NamespacedName{Namespace: va.Namespace, Name: st.Name}, &pvc); err != nil {
+			return err
+		}
+		patch := client.MergeFrom(pvc.DeepCopy())
+		if pvc.Annotations == nil {
+			pvc.Annotations = map[string]string{}
+		}
+		pvc.Annotations[controller.AnnotationAnomalyAck] = stamp
+		if err := c.Patch(ctx, &pvc, patch); err != nil {
+			return fmt.Errorf("annotating PVC %s: %w", st.Name, err)
+		}
+		fmt.Fprintf(out, "persistentvolumeclaim/%s %s anomaly acknowledged\n", st.Name, st.Anomaly.Kind)
+		acked++
+	}
+	if acked == 0 {
+		fmt.Fprintln(out, "No unacknowledged anomalies.")
+	}
+	return nil
+}
+
+// setSuspended toggles spec.suspend on a VolumeAutoscaler.
+func setSuspended(ctx context.Context, c client.Client, opts options, name string, suspend bool, out io.Writer) error {
+	var va autoscalingv1beta1.VolumeAutoscaler
+	if err := c.Get(ctx, types.NamespacedName{Namespace: opts.namespace, Name: name}, &va); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(va.DeepCopy())
+	va.Spec.Suspend = suspend
+	verb := "paused"
+	if !suspend {
+		verb = "resumed"
+	}
+	if err := c.Patch(ctx, &va, patch); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "volumeautoscaler/%s %s\n", name, verb)
+	return nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/controller"
+)
+
+func TestPrintStatus(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	lastScale := metav1.NewTime(now.Add(-2 * time.Minute))
+	lastSize := resource.MustParse("12Gi")
+
+	vas := []autoscalingv1beta1.VolumeAutoscaler{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"},
+			Spec:       autoscalingv1beta1.VolumeAutoscalerSpec{Trigger: autoscalingv1beta1.TriggerSpec{ThresholdPercent: 75}},
+			Status: autoscalingv1beta1.VolumeAutoscalerStatus{PVCs: []autoscalingv1beta1.PVCStatus{{
+				Name:          "data-db-0",
+				CurrentSize:   resource.MustParse("12Gi"),
+				UsagePercent:  60,
+				LastScaleTime: &lastScale,
+				LastScaleSize: &lastSize,
+			}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "apps"},
+			Spec:       autoscalingv1beta1.VolumeAutoscalerSpec{Suspend: true},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := printStatus(&out, vas, 80, now); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got:\n%s", out.String())
+	}
+	for _, want := range []string{"data-db-0", "12Gi", "60%", "75%", "15%", "3m0s", "2m0s ago (12Gi)"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("row %q missing %q", lines[1], want)
+		}
+	}
+	for _, want := range []string{"cache (paused)", "<none>", "80%"} {
+		if !strings.Contains(lines[2], want) {
+			t.Errorf("row %q missing %q", lines[2], want)
+		}
+	}
+}
+
+func TestRunRejectsUnknownCommand(t *testing.T) {
+	var out bytes.Buffer
+	if err := run(t.Context(), []string{"scale"}, &out); err == nil {
+		t.Fatal("expected an error for an unknown command")
+	}
+	if err := run(t.Context(), []string{"help"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "expand-now") {
+		t.Errorf("usage missing commands:\n%s", out.String())
+	}
+}
+
+// fakeCluster points newClient at a fake client holding objs, with "default"
+// as the kubeconfig namespace.
+func fakeCluster(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := autoscalingv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	orig := newClient
+	newClient = func(options) (client.Client, string, error) { return c, "default", nil }
+	t.Cleanup(func() { newClient = orig })
+	return c
+}
+
+func TestRunParsesFlagsAfterName(t *testing.T) {
+	pvc := func(namespace, name string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+	c := fakeCluster(t,
+		&autoscalingv1beta1.VolumeAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"},
+			Status: autoscalingv1beta1.VolumeAutoscalerStatus{PVCs: []autoscalingv1beta1.PVCStatus{
+				{Name: "data-db-0"}, {Name: "data-db-1"},
+			}},
+		},
+		pvc("apps", "data-db-0"), pvc("apps", "data-db-1"), pvc("default", "data-db-0"),
+	)
+
+	var out bytes.Buffer
+	if err := run(t.Context(), []string{"expand-now", "db", "-n", "apps", "--pvc", "data-db-1"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		namespace, name string
+		want            bool
+	}{
+		{"apps", "data-db-0", false},
+		{"apps", "data-db-1", true},
+		{"default", "data-db-0", false},
+	} {
+		var got corev1.PersistentVolumeClaim
+		if err := c.Get(t.Context(), types.NamespacedName{Namespace: tc.namespace, Name: tc.name}, &got); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := got.Annotations[controller.AnnotationExpandNow]; ok != tc.want {
+			t.Errorf("%s/%s annotated = %v, want %v", tc.namespace, tc.name, ok, tc.want)
+		}
+	}
+
+	if err := run(t.Context(), []string{"expand-now", "db", "-n", "apps", "extra"}, &out); err == nil {
+		t.Error("expected an error for an extra argument")
+	}
+}
+
+func TestRunStatusUsesConfigThreshold(t *testing.T) {
+	fakeCluster(t, &autoscalingv1beta1.VolumeAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+	})
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("thresholdPercent: 70\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := run(t.Context(), []string{"status", "db", "--config", path}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "70%") {
+		t.Errorf("status ignored the config threshold:\n%s", out.String())
+	}
+}
@@ -17,21 +17,42 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	autoscalingv1alpha1 "github.com/volume-autoscaler/volume-autoscaler/api/v1alpha1"
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/aggregator"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/config"
 	"github.com/volume-autoscaler/volume-autoscaler/internal/controller"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/decision"
 	_ "github.com/volume-autoscaler/volume-autoscaler/internal/metrics"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/migration"
+	promclient "github.com/volume-autoscaler/volume-autoscaler/internal/prometheus"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/ratelimit"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/tracing"
+	webhookv1beta1 "github.com/volume-autoscaler/volume-autoscaler/internal/webhook/v1beta1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -42,7 +63,9 @@ var (
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 	utilruntime.Must(autoscalingv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(autoscalingv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -50,6 +73,17 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var maxConcurrentReconciles int
+	var reconcileTimeout time.Duration
+	var shard string
+	var configFile string
+	var aggregateURL string
+	var aggregateAddr string
+	var aggregateOpts aggregator.Options
+	var traceOpts tracing.Options
+	var webhookCertPath string
+	var decisionLogSize int
+	var decisionConfigMap string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080",
 		"The address the metrics endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081",
@@ -57,6 +91,44 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"Number of VolumeAutoscalers reconciled in parallel.")
+	flag.DurationVar(&reconcileTimeout, "reconcile-timeout", 2*time.Minute,
+		"Upper bound for a single reconcile, including Prometheus queries. 0 disables the timeout.")
+	flag.StringVar(&configFile, "config", "",
+		"Path to the controller config file (ControllerConfig). It is reloaded on change. "+
+			"Empty uses built-in defaults.")
+	flag.StringVar(&shard, "shard", "",
+		"Only reconcile VolumeAutoscalers labeled "+controller.ShardLabel+"=<shard>. "+
+			"Each shard elects its own leader and applies the config's resizeLimits on its own, "+
+			"so N shards together may resize up to N times those limits. Empty reconciles all VolumeAutoscalers.")
+	flag.StringVar(&webhookCertPath, "webhook-cert-path", "",
+		"Directory holding the conversion webhook's tls.crt and tls.key. "+
+			"Empty uses /tmp/k8s-webhook-server/serving-certs.")
+	flag.IntVar(&decisionLogSize, "decision-log-size", 1000,
+		"Number of per-PVC decision records kept in memory and served on the metrics address at "+
+			"/debug/decisions. 0 disables the decision log.")
+	flag.StringVar(&decisionConfigMap, "decision-log-configmap", "",
+		"Also write the decision log to this ConfigMap (namespace/name) every minute. Empty disables it.")
+	flag.StringVar(&traceOpts.Endpoint, "otlp-endpoint", "",
+		"OTLP/gRPC collector address (host:port) to export traces of reconciles, Prometheus queries "+
+			"and API calls to. Empty disables tracing.")
+	flag.BoolVar(&traceOpts.Insecure, "otlp-insecure", false,
+		"Connect to the OTLP collector without TLS.")
+	flag.Float64Var(&traceOpts.SampleRatio, "trace-sample-ratio", 1,
+		"Fraction of reconciles to trace, between 0 and 1.")
+	flag.StringVar(&aggregateURL, "aggregate-prometheus-url", "",
+		"Run in read-only aggregator mode instead of reconciling: serve a cross-cluster report "+
+			"built from this central Prometheus or Thanos.")
+	flag.StringVar(&aggregateAddr, "aggregate-bind-address", ":8090",
+		"The address the aggregator report binds to.")
+	flag.StringVar(&aggregateOpts.ClusterLabel, "aggregate-cluster-label", "cluster",
+		"Series label that identifies the cluster in the central Prometheus.")
+	var nearMargin int
+	flag.IntVar(&nearMargin, "aggregate-near-threshold-margin", 10,
+		"Report PVCs within this many percentage points of their threshold.")
+	flag.DurationVar(&aggregateOpts.StuckAfter, "aggregate-stuck-after", 30*time.Minute,
+		"Report PVCs whose last expansion has not completed after this long.")
 
 	opts := zap.Options{
 		Development: true,
@@ -65,29 +137,135 @@ func main() {
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	ctx := ctrl.SetupSignalHandler()
+
+	shutdownTracing, err := tracing.Setup(ctx, "volume-autoscaler", traceOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			setupLog.Error(err, "failed to flush traces")
+		}
+	}()
+
+	if aggregateURL != "" {
+		aggregateOpts.NearThresholdMargin = int32(nearMargin)
+		if err := runAggregator(ctx, aggregateURL, aggregateAddr, aggregateOpts); err != nil {
+			setupLog.Error(err, "problem running aggregator")
+			os.Exit(1)
+		}
+		return
+	}
+
+	var decisions *decision.Log
+	metricsHandlers := map[string]http.Handler{}
+	if decisionLogSize > 0 {
+		decisions = decision.NewLog(decisionLogSize)
+		metricsHandlers["/debug/decisions"] = decisions.Handler()
+	}
+	var decisionKey types.NamespacedName
+	if decisionConfigMap != "" {
+		ns, name, ok := strings.Cut(decisionConfigMap, "/")
+		if !ok || ns == "" || name == "" || decisions == nil {
+			setupLog.Error(errors.New("want namespace/name and a positive --decision-log-size"),
+				"invalid --decision-log-configmap", "value", decisionConfigMap)
+			os.Exit(1)
+		}
+		decisionKey = types.NamespacedName{Namespace: ns, Name: name}
+	}
+
+	leaderElectionID := "volume-autoscaler.io"
+	if shard != "" {
+		leaderElectionID = shard + "." + leaderElectionID
+	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
-			BindAddress: metricsAddr,
+			BindAddress:   metricsAddr,
+			ExtraHandlers: metricsHandlers,
 		},
+		WebhookServer:          webhook.NewServer(webhook.Options{CertDir: webhookCertPath}),
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "volume-autoscaler.io",
+		LeaderElectionID:       leaderElectionID,
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				// Pods and StatefulSets are only read when resolving
+				// spec.target.ownerRef, PVs and CSIStorageCapacities by the
+				// backend capacity check and Events, by field selector, by the
+				// volume health check; caching them all cluster-wide is not
+				// worth the memory.
+				DisableFor: []client.Object{
+					&corev1.Pod{}, &appsv1.StatefulSet{},
+					&corev1.PersistentVolume{}, &storagev1.CSIStorageCapacity{},
+					&corev1.Event{},
+				},
+			},
+		},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	cfgStore, err := config.NewStore(configFile)
+	if err != nil {
+		setupLog.Error(err, "unable to load controller config", "path", configFile)
+		os.Exit(1)
+	}
+	if err := mgr.Add(cfgStore); err != nil {
+		setupLog.Error(err, "unable to watch controller config")
+		os.Exit(1)
+	}
+
 	if err := (&controller.VolumeAutoscalerReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorder("volume-autoscaler"),
+
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		ReconcileTimeout:        reconcileTimeout,
+		Shard:                   shard,
+		Config:                  cfgStore,
+		Decisions:               decisions,
+		ResizeLimiter:           ratelimit.New(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "VolumeAutoscaler")
 		os.Exit(1)
 	}
+	if decisionKey.Name != "" {
+		if err := mgr.Add(&decision.ConfigMapWriter{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Log:       decisions,
+			Key:       decisionKey,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up decision log ConfigMap")
+			os.Exit(1)
+		}
+	}
+	// The conversion webhook serves v1alpha1 clients from v1beta1 storage. Set
+	// ENABLE_WEBHOOKS=false to run locally without serving certificates.
+	webhooksEnabled := os.Getenv("ENABLE_WEBHOOKS") != "false"
+	if webhooksEnabled {
+		if err := webhookv1beta1.SetupVolumeAutoscalerWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "VolumeAutoscaler")
+			os.Exit(1)
+		}
+		// Stored v1alpha1 objects can only be read through the webhook
+		if err := mgr.Add(&migration.StorageVersionMigrator{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up storage version migration")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -98,10 +276,40 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if webhooksEnabled {
+		if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
+			setupLog.Error(err, "unable to set up webhook ready check")
+			os.Exit(1)
+		}
+	}
 
-	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	setupLog.Info("starting manager", "shard", shard, "maxConcurrentReconciles", maxConcurrentReconciles)
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// runAggregator serves the cross-cluster report until ctx is done. It needs
+// no Kubernetes API access.
+func runAggregator(ctx context.Context, promURL, addr string, opts aggregator.Options) error {
+	agg := aggregator.New(promclient.NewClient(promURL), opts)
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           agg.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	setupLog.Info("starting aggregator", "prometheusURL", promURL, "address", addr,
+		"clusterLabel", opts.ClusterLabel)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package aggregator builds a read-only, cross-cluster report of managed PVCs
+// from a central Prometheus or Thanos that holds every cluster's
+// volume_autoscaler_* and kubelet volume metrics. It needs no access to the
+// clusters themselves.
+package aggregator
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/utils/clock"
+
+	promclient "github.com/volume-autoscaler/volume-autoscaler/internal/prometheus"
+)
+
+// States a PVC can be reported in.
+const (
+	// StateNearThreshold means usage is within the margin of the threshold or above it.
+	StateNearThreshold = "NearThreshold"
+	// StateAtMaxSize means the volume has reached its maxSize and will not be expanded again.
+	StateAtMaxSize = "AtMaxSize"
+	// StateStuckResizing means the last expansion has not completed within StuckAfter.
+	StateStuckResizing = "StuckResizing"
+)
+
+// Options tune how the report is built.
+type Options struct {
+	// ClusterLabel is the series label that tells clusters apart, usually a
+	// Prometheus external label.
+	ClusterLabel string
+	// NearThresholdMargin is how many percentage points below its threshold
+	// a PVC is reported as NearThreshold.
+	NearThresholdMargin int32
+	// StuckAfter is how long an expansion may stay incomplete before the PVC
+	// is reported as StuckResizing.
+	StuckAfter time.Duration
+}
+
+// Report lists, per cluster, the managed PVCs that need attention.
+type Report struct {
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Clusters    []ClusterReport `json:"clusters"`
+}
+
+// ClusterReport is the part of a Report for one cluster.
+type ClusterReport struct {
+	Name string `json:"name"`
+	// ManagedPVCs counts every PVC a VolumeAutoscaler reports usage for.
+	ManagedPVCs int `json:"managedPVCs"`
+	// PVCs holds the managed PVCs in at least one state, by namespace and name.
+	PVCs []PVCReport `json:"pvcs"`
+}
+
+// PVCReport describes one managed PVC.
+type PVCReport struct {
+	Namespace            string   `json:"namespace"`
+	PVC                  string   `json:"pvc"`
+	VolumeAutoscaler     string   `json:"volumeAutoscaler"`
+	UsagePercent         float64  `json:"usagePercent"`
+	ThresholdPercent     float64  `json:"thresholdPercent"`
+	UsedBytes            int64    `json:"usedBytes,omitempty"`
+	CapacityBytes        int64    `json:"capacityBytes,omitempty"`
+	SizeBytes            int64    `json:"sizeBytes,omitempty"`
+	MaxSizeBytes         int64    `json:"maxSizeBytes,omitempty"`
+	ResizePendingSeconds float64  `json:"resizePendingSeconds,omitempty"`
+	States               []string `json:"states"`
+}
+
+// Aggregator queries the central Prometheus and builds Reports.
+type Aggregator struct {
+	prom  *promclient.Client
+	opts  Options
+	clock clock.PassiveClock
+}
+
+// New returns an Aggregator reading from prom.
+func New(prom *promclient.Client, opts Options) *Aggregator {
+	return &Aggregator{prom: prom, opts: opts, clock: clock.RealClock{}}
+}
+
+// pvcKey identifies a managed PVC across clusters.
+type pvcKey struct {
+	cluster, namespace, pvc, volumeAutoscaler string
+}
+
+// volumeKey identifies a PVC in the kubelet metrics, which have no
+// volumeautoscaler label.
+type volumeKey struct {
+	cluster, namespace, pvc string
+}
+
+// Build queries Prometheus and returns the current report.
+func (a *Aggregator) Build(ctx context.Context) (*Report, error) {
+	cl := a.opts.ClusterLabel
+
+	// max by drops the pod and instance labels, so a stale series left
+	// behind by a former leader does not show up as a second PVC
+	byPVC := func(metric string) string {
+		return fmt.Sprintf("max by (%s, namespace, pvc, volumeautoscaler) (%s)", cl, metric)
+	}
+	byVolume := func(metric string) string {
+		return fmt.Sprintf("max by (%s, namespace, persistentvolumeclaim) (%s)", cl, metric)
+	}
+
+	usage, err := a.prom.QueryVector(ctx, byPVC("volume_autoscaler_pvc_usage_percent"))
+	if err != nil {
+		return nil, fmt.Errorf("querying usage: %w", err)
+	}
+	pvcs := make(map[pvcKey]*PVCReport, len(usage))
+	for _, s := range usage {
+		key := a.pvcKey(s)
+		pvcs[key] = &PVCReport{
+			Namespace:        key.namespace,
+			PVC:              key.pvc,
+			VolumeAutoscaler: key.volumeAutoscaler,
+			UsagePercent:     s.Value,
+		}
+	}
+
+	perPVC := []struct {
+		metric string
+		set    func(*PVCReport, float64)
+	}{
+		{"volume_autoscaler_pvc_threshold_percent", func(p *PVCReport, v float64) { p.ThresholdPercent = v }},
+		{"volume_autoscaler_pvc_size_bytes", func(p *PVCReport, v float64) { p.SizeBytes = int64(v) }},
+		{"volume_autoscaler_pvc_max_size_bytes", func(p *PVCReport, v float64) { p.MaxSizeBytes = int64(v) }},
+		{"volume_autoscaler_pvc_resize_pending_seconds", func(p *PVCReport, v float64) { p.ResizePendingSeconds = v }},
+	}
+	for _, q := range perPVC {
+		samples, err := a.prom.QueryVector(ctx, byPVC(q.metric))
+		if err != nil {
+			return nil, fmt.Errorf("querying %s: %w", q.metric, err)
+		}
+		for _, s := range samples {
+			if p, ok := pvcs[a.pvcKey(s)]; ok {
+				q.set(p, s.Value)
+			}
+		}
+	}
+
+	perVolume := []struct {
+		metric string
+		set    func(*PVCReport, float64)
+	}{
+		{"kubelet_volume_stats_used_bytes", func(p *PVCReport, v float64) { p.UsedBytes = int64(v) }},
+		{"kubelet_volume_stats_capacity_bytes", func(p *PVCReport, v float64) { p.CapacityBytes = int64(v) }},
+	}
+	byVolumeKey := make(map[volumeKey][]*PVCReport, len(pvcs))
+	for key, p := range pvcs {
+		vk := volumeKey{key.cluster, key.namespace, key.pvc}
+		byVolumeKey[vk] = append(byVolumeKey[vk], p)
+	}
+	for _, q := range perVolume {
+		samples, err := a.prom.QueryVector(ctx, byVolume(q.metric))
+		if err != nil {
+			return nil, fmt.Errorf("querying %s: %w", q.metric, err)
+		}
+		for _, s := range samples {
+			vk := volumeKey{s.Labels[cl], s.Labels["namespace"], s.Labels["persistentvolumeclaim"]}
+			for _, p := range byVolumeKey[vk] {
+				q.set(p, s.Value)
+			}
+		}
+	}
+
+	clusters := map[string]*ClusterReport{}
+	for key, p := range pvcs {
+		c, ok := clusters[key.cluster]
+		if !ok {
+			c = &ClusterReport{Name: key.cluster, PVCs: []PVCReport{}}
+			clusters[key.cluster] = c
+		}
+		c.ManagedPVCs++
+		if p.States = a.states(p); len(p.States) > 0 {
+			c.PVCs = append(c.PVCs, *p)
+		}
+	}
+
+	report := &Report{GeneratedAt: a.clock.Now().UTC(), Clusters: make([]ClusterReport, 0, len(clusters))}
+	for _, c := range clusters {
+		slices.SortFunc(c.PVCs, func(x, y PVCReport) int {
+			return cmp.Or(strings.Compare(x.Namespace, y.Namespace), strings.Compare(x.PVC, y.PVC),
+				strings.Compare(x.VolumeAutoscaler, y.VolumeAutoscaler))
+		})
+		report.Clusters = append(report.Clusters, *c)
+	}
+	slices.SortFunc(report.Clusters, func(x, y ClusterReport) int { return strings.Compare(x.Name, y.Name) })
+	return report, nil
+}
+
+func (a *Aggregator) pvcKey(s promclient.Sample) pvcKey {
+	return pvcKey{
+		cluster:          s.Labels[a.opts.ClusterLabel],
+		namespace:        s.Labels["namespace"],
+		pvc:              s.Labels["pvc"],
+		volumeAutoscaler: s.Labels["volumeautoscaler"],
+	}
+}
+
+// states returns the states the PVC is in, in a fixed order.
+func (a *Aggregator) states(p *PVCReport) []string {
+	var states []string
+	if p.ThresholdPercent > 0 && p.UsagePercent >= p.ThresholdPercent-float64(a.opts.NearThresholdMargin) {
+		states = append(states, StateNearThreshold)
+	}
+	if p.MaxSizeBytes > 0 && p.SizeBytes >= p.MaxSizeBytes {
+		states = append(states, StateAtMaxSize)
+	}
+	if p.ResizePendingSeconds > 0 && p.ResizePendingSeconds >= a.opts.StuckAfter.Seconds() {
+		states = append(states, StateStuckResizing)
+	}
+	return states
+}
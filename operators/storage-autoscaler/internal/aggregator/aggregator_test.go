@@ -0,0 +1,136 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	promclient "github.com/volume-autoscaler/volume-autoscaler/internal/prometheus"
+)
+
+// series maps a metric name to the result the fake Prometheus returns for it.
+var series = map[string]string{
+	"volume_autoscaler_pvc_usage_percent": `[
+		{"metric": {"cluster": "prod", "namespace": "monitoring", "pvc": "loki-0", "volumeautoscaler": "loki"}, "value": [1, "85"]},
+		{"metric": {"cluster": "prod", "namespace": "monitoring", "pvc": "prom-0", "volumeautoscaler": "prom"}, "value": [1, "40"]},
+		{"metric": {"cluster": "dev", "namespace": "gitlab", "pvc": "repo-0", "volumeautoscaler": "gitaly"}, "value": [1, "30"]}
+	]`,
+	"volume_autoscaler_pvc_threshold_percent": `[
+		{"metric": {"cluster": "prod", "namespace": "monitoring", "pvc": "loki-0", "volumeautoscaler": "loki"}, "value": [1, "80"]},
+		{"metric": {"cluster": "prod", "namespace": "monitoring", "pvc": "prom-0", "volumeautoscaler": "prom"}, "value": [1, "80"]},
+		{"metric": {"cluster": "dev", "namespace": "gitlab", "pvc": "repo-0", "volumeautoscaler": "gitaly"}, "value": [1, "80"]}
+	]`,
+	"volume_autoscaler_pvc_size_bytes": `[
+		{"metric": {"cluster": "prod", "namespace": "monitoring", "pvc": "prom-0", "volumeautoscaler": "prom"}, "value": [1, "107374182400"]}
+	]`,
+	"volume_autoscaler_pvc_max_size_bytes": `[
+		{"metric": {"cluster": "prod", "namespace": "monitoring", "pvc": "prom-0", "volumeautoscaler": "prom"}, "value": [1, "107374182400"]}
+	]`,
+	"volume_autoscaler_pvc_resize_pending_seconds": `[
+		{"metric": {"cluster": "dev", "namespace": "gitlab", "pvc": "repo-0", "volumeautoscaler": "gitaly"}, "value": [1, "3600"]}
+	]`,
+	"kubelet_volume_stats_used_bytes": `[
+		{"metric": {"cluster": "prod", "namespace": "monitoring", "persistentvolumeclaim": "loki-0"}, "value": [1, "42949672960"]},
+		{"metric": {"cluster": "prod", "namespace": "other", "persistentvolumeclaim": "unmanaged"}, "value": [1, "1"]}
+	]`,
+}
+
+func fakePrometheus(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		if !strings.HasPrefix(query, "max by (cluster, ") {
+			t.Errorf("query not aggregated by cluster: %s", query)
+		}
+		result := "[]"
+		for metric, res := range series {
+			if strings.Contains(query, "("+metric+")") {
+				result = res
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": "success", "data": {"resultType": "vector", "result": ` + result + `}}`))
+	}))
+}
+
+func newAggregator(url string) *Aggregator {
+	return New(promclient.NewClient(url), Options{
+		ClusterLabel:        "cluster",
+		NearThresholdMargin: 10,
+		StuckAfter:          30 * time.Minute,
+	})
+}
+
+func TestBuild(t *testing.T) {
+	server := fakePrometheus(t)
+	defer server.Close()
+
+	report, err := newAggregator(server.URL).Build(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Clusters) != 2 || report.Clusters[0].Name != "dev" || report.Clusters[1].Name != "prod" {
+		t.Fatalf("unexpected clusters: %+v", report.Clusters)
+	}
+
+	dev, prod := report.Clusters[0], report.Clusters[1]
+	if prod.ManagedPVCs != 2 || len(prod.PVCs) != 2 {
+		t.Fatalf("prod: managed %d, flagged %d, want 2 and 2", prod.ManagedPVCs, len(prod.PVCs))
+	}
+	loki, prom := prod.PVCs[0], prod.PVCs[1]
+	if !slices.Equal(loki.States, []string{StateNearThreshold}) || loki.UsedBytes != 40<<30 {
+		t.Errorf("loki-0 = %+v, want NearThreshold with 40Gi used", loki)
+	}
+	if !slices.Equal(prom.States, []string{StateAtMaxSize}) {
+		t.Errorf("prom-0 states = %v, want AtMaxSize", prom.States)
+	}
+	if len(dev.PVCs) != 1 || !slices.Equal(dev.PVCs[0].States, []string{StateStuckResizing}) {
+		t.Errorf("dev PVCs = %+v, want repo-0 StuckResizing", dev.PVCs)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	server := fakePrometheus(t)
+	defer server.Close()
+	handler := newAggregator(server.URL).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/report?cluster=prod&state=AtMaxSize", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body: %s", rec.Code, rec.Body)
+	}
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decoding report: %v", err)
+	}
+	if len(report.Clusters) != 1 || len(report.Clusters[0].PVCs) != 1 || report.Clusters[0].PVCs[0].PVC != "prom-0" {
+		t.Errorf("unexpected filtered report: %+v", report)
+	}
+
+	server.Close()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/report", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status with Prometheus down = %d, want 502", rec.Code)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+)
+
+// Handler returns the HTTP handler for the report:
+//
+//	GET /report                          every cluster
+//	GET /report?cluster=prod             one cluster
+//	GET /report?state=StuckResizing      only PVCs in the given state
+//	GET /healthz                         liveness
+//
+// Each request queries Prometheus afresh.
+func (a *Aggregator) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /report", a.serveReport)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	return mux
+}
+
+func (a *Aggregator) serveReport(w http.ResponseWriter, r *http.Request) {
+	report, err := a.Build(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+	report.filter(r.URL.Query().Get("cluster"), r.URL.Query().Get("state"))
+	writeJSON(w, http.StatusOK, report)
+}
+
+// filter keeps only the named cluster and the PVCs in the given state. An
+// empty argument does not filter.
+func (r *Report) filter(cluster, state string) {
+	if cluster != "" {
+		r.Clusters = slices.DeleteFunc(r.Clusters, func(c ClusterReport) bool { return c.Name != cluster })
+	}
+	if state != "" {
+		for i := range r.Clusters {
+			r.Clusters[i].PVCs = slices.DeleteFunc(r.Clusters[i].PVCs, func(p PVCReport) bool {
+				return !slices.Contains(p.States, state)
+			})
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package capacity checks whether the storage backend has room for a PVC
+// expansion before the controller patches the PVC.
+package capacity
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/volume-autoscaler/volume-autoscaler/internal/config"
+)
+
+// Checker reports whether a storage backend can grow a PVC to newSize.
+type Checker interface {
+	// Name identifies the backend in messages.
+	Name() string
+
+	// Check returns an *InsufficientError when the backend lacks room for
+	// the expansion, and nil when it has room or cannot tell. Other errors
+	// mean the backend could not be queried.
+	Check(ctx context.Context, c client.Reader, pvc *corev1.PersistentVolumeClaim, newSize resource.Quantity) error
+}
+
+// InsufficientError reports a backend without room for an expansion.
+type InsufficientError struct {
+	// Backend is the name of the checker that refused the expansion.
+	Backend string
+	// Needed is the additional space the expansion requires.
+	Needed resource.Quantity
+	// Available is the most space the backend reported free.
+	Available resource.Quantity
+	// Detail says where the space is short, e.g. a topology segment or disk.
+	Detail string
+}
+
+func (e *InsufficientError) Error() string {
+	msg := fmt.Sprintf("%s has %s available, expansion needs %s", e.Backend, gib(e.Available), gib(e.Needed))
+	if e.Detail != "" {
+		msg += " (" + e.Detail + ")"
+	}
+	return msg
+}
+
+// gib formats a quantity in GiB; the growth of a percentage-based
+// expansion is rarely a round number of bytes.
+func gib(q resource.Quantity) string {
+	return fmt.Sprintf("%.1fGi", float64(q.Value())/(1<<30))
+}
+
+// FromConfig returns the checkers enabled in cfg, in the order they run.
+func FromConfig(cfg config.BackendCapacity) []Checker {
+	var checkers []Checker
+	if cfg.CSIStorageCapacity {
+		checkers = append(checkers, CSIStorageCapacity{})
+	}
+	if cfg.LonghornNamespace != "" {
+		checkers = append(checkers, Longhorn{Namespace: cfg.LonghornNamespace})
+	}
+	return checkers
+}
+
+// Check runs each checker in turn and returns the first error.
+func Check(
+	ctx context.Context,
+	c client.Reader,
+	checkers []Checker,
+	pvc *corev1.PersistentVolumeClaim,
+	newSize resource.Quantity,
+) error {
+	for _, checker := range checkers {
+		if err := checker.Check(ctx, c, pvc, newSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// growth returns the space an expansion of pvc to newSize adds.
+func growth(pvc *corev1.PersistentVolumeClaim, newSize resource.Quantity) resource.Quantity {
+	needed := newSize.DeepCopy()
+	needed.Sub(pvc.Status.Capacity[corev1.ResourceStorage])
+	return needed
+}
+
+// boundVolume returns the PV bound to pvc, or nil if it is not bound.
+func boundVolume(ctx context.Context, c client.Reader, pvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolume, error) {
+	if pvc.Spec.VolumeName == "" {
+		return nil, nil
+	}
+	var pv corev1.PersistentVolume
+	if err := c.Get(ctx, types.NamespacedName{Name: pvc.Spec.VolumeName}, &pv); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return &pv, nil
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacity
+
+import (
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const gi = int64(1) << 30
+
+func newPVC(sc, volume, size string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"},
+		Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &sc, VolumeName: volume},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+		},
+	}
+}
+
+func csiCapacity(name, sc, zone string, capacity, maxSize *resource.Quantity) *storagev1.CSIStorageCapacity {
+	obj := &storagev1.CSIStorageCapacity{
+		ObjectMeta:        metav1.ObjectMeta{Name: name, Namespace: "kube-system"},
+		StorageClassName:  sc,
+		Capacity:          capacity,
+		MaximumVolumeSize: maxSize,
+	}
+	if zone != "" {
+		obj.NodeTopology = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"topology.kubernetes.io/zone": zone},
+		}
+	}
+	return obj
+}
+
+func quantity(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
+func zonalPV(name, zone string) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PersistentVolumeSpec{
+			NodeAffinity: &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{zone},
+				}}}},
+			}},
+		},
+	}
+}
+
+func TestCSIStorageCapacity(t *testing.T) {
+	tests := map[string]struct {
+		objects      []client.Object
+		newSize      string
+		insufficient bool
+	}{
+		"no capacity published": {
+			newSize: "20Gi",
+		},
+		"enough capacity": {
+			objects: []client.Object{csiCapacity("a", "fast", "", quantity("50Gi"), nil)},
+			newSize: "20Gi",
+		},
+		"not enough capacity": {
+			objects:      []client.Object{csiCapacity("a", "fast", "", quantity("5Gi"), nil)},
+			newSize:      "20Gi",
+			insufficient: true,
+		},
+		"other StorageClass ignored": {
+			objects: []client.Object{csiCapacity("a", "slow", "", quantity("1Gi"), nil)},
+			newSize: "20Gi",
+		},
+		"unknown capacity": {
+			objects: []client.Object{csiCapacity("a", "fast", "", nil, nil)},
+			newSize: "20Gi",
+		},
+		"maximum volume size exceeded": {
+			objects:      []client.Object{csiCapacity("a", "fast", "", quantity("500Gi"), quantity("16Gi"))},
+			newSize:      "20Gi",
+			insufficient: true,
+		},
+		"only the volume's zone counts": {
+			objects: []client.Object{
+				zonalPV("pv-1", "zone-a"),
+				csiCapacity("a", "fast", "zone-a", quantity("5Gi"), nil),
+				csiCapacity("b", "fast", "zone-b", quantity("500Gi"), nil),
+			},
+			newSize:      "20Gi",
+			insufficient: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(tt.objects...).Build()
+			err := CSIStorageCapacity{}.Check(t.Context(), c, newPVC("fast", "pv-1", "10Gi"), resource.MustParse(tt.newSize))
+			var short *InsufficientError
+			if got := errors.As(err, &short); got != tt.insufficient {
+				t.Fatalf("insufficient = %v, want %v (err: %v)", got, tt.insufficient, err)
+			}
+			if !tt.insufficient && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func longhornObject(kind, name string, fields map[string]any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: fields}
+	obj.SetGroupVersionKind(longhornGroupVersion.WithKind(kind))
+	obj.SetNamespace("longhorn-system")
+	obj.SetName(name)
+	return obj
+}
+
+func longhornScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	for _, kind := range []string{"Node", "Replica", "Setting"} {
+		scheme.AddKnownTypeWithName(longhornGroupVersion.WithKind(kind), &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(longhornGroupVersion.WithKind(kind+"List"), &unstructured.UnstructuredList{})
+	}
+	return scheme
+}
+
+func TestLonghorn(t *testing.T) {
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-123"},
+		Spec: corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{
+			CSI: &corev1.CSIPersistentVolumeSource{Driver: longhornDriver, VolumeHandle: "pvc-123"},
+		}},
+	}
+	replica := func(name, node, disk string) client.Object {
+		r := longhornObject("Replica", name, map[string]any{
+			"spec": map[string]any{"volumeName": "pvc-123", "nodeID": node, "diskID": disk},
+		})
+		r.SetLabels(map[string]string{"longhornvolume": "pvc-123"})
+		return r
+	}
+	node := func(name, uuid string, available, scheduled int64) client.Object {
+		return longhornObject("Node", name, map[string]any{
+			"spec": map[string]any{"disks": map[string]any{
+				"default-disk": map[string]any{"storageReserved": 10 * gi},
+			}},
+			"status": map[string]any{"diskStatus": map[string]any{
+				"default-disk": map[string]any{
+					"diskUUID":         uuid,
+					"storageMaximum":   100 * gi,
+					"storageAvailable": available,
+					"storageScheduled": scheduled,
+				},
+			}},
+		})
+	}
+
+	tests := map[string]struct {
+		objects      []client.Object
+		insufficient bool
+	}{
+		"room on every replica disk": {
+			objects: []client.Object{
+				replica("r1", "node-1", "uuid-1"), node("node-1", "uuid-1", 60*gi, 40*gi),
+				replica("r2", "node-2", "uuid-2"), node("node-2", "uuid-2", 60*gi, 40*gi),
+			},
+		},
+		"minimal available space breached": {
+			objects: []client.Object{
+				replica("r1", "node-1", "uuid-1"), node("node-1", "uuid-1", 60*gi, 40*gi),
+				replica("r2", "node-2", "uuid-2"), node("node-2", "uuid-2", 30*gi, 40*gi),
+			},
+			insufficient: true,
+		},
+		"over-provisioning limit reached": {
+			objects: []client.Object{
+				replica("r1", "node-1", "uuid-1"), node("node-1", "uuid-1", 60*gi, 85*gi),
+			},
+			insufficient: true,
+		},
+		"over-provisioning setting raises the limit": {
+			objects: []client.Object{
+				replica("r1", "node-1", "uuid-1"), node("node-1", "uuid-1", 60*gi, 85*gi),
+				longhornObject("Setting", settingOverProvisioning, map[string]any{"value": "200"}),
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(longhornScheme()).
+				WithObjects(append(tt.objects, pv)...).Build()
+			err := Longhorn{Namespace: "longhorn-system"}.Check(t.Context(), c,
+				newPVC("longhorn", "pvc-123", "10Gi"), resource.MustParse("20Gi"))
+			var short *InsufficientError
+			if got := errors.As(err, &short); got != tt.insufficient {
+				t.Fatalf("insufficient = %v, want %v (err: %v)", got, tt.insufficient, err)
+			}
+			if !tt.insufficient && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestLonghornIgnoresOtherDrivers(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	err := Longhorn{Namespace: "longhorn-system"}.Check(t.Context(), c,
+		newPVC("fast", "", "10Gi"), resource.MustParse("20Gi"))
+	if err != nil {
+		t.Fatalf("unexpected error for an unbound PVC: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacity
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CSIStorageCapacity checks the growth against the CSIStorageCapacity
+// objects CSI drivers publish for the PVC's StorageClass. When the PV pins
+// the volume to a topology segment, only objects covering that segment are
+// considered. It has no opinion if the driver publishes no capacity.
+type CSIStorageCapacity struct{}
+
+// Name implements Checker.
+func (CSIStorageCapacity) Name() string { return "CSIStorageCapacity" }
+
+// Check implements Checker.
+func (s CSIStorageCapacity) Check(
+	ctx context.Context,
+	c client.Reader,
+	pvc *corev1.PersistentVolumeClaim,
+	newSize resource.Quantity,
+) error {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return nil
+	}
+	var list storagev1.CSIStorageCapacityList
+	if err := c.List(ctx, &list); err != nil {
+		return fmt.Errorf("listing CSIStorageCapacity: %w", err)
+	}
+	pv, err := boundVolume(ctx, c, pvc)
+	if err != nil {
+		return fmt.Errorf("getting PV %s: %w", pvc.Spec.VolumeName, err)
+	}
+	topology := volumeTopology(pv)
+
+	needed := growth(pvc, newSize)
+	var best, capped *storagev1.CSIStorageCapacity
+	for i := range list.Items {
+		sc := &list.Items[i]
+		if sc.StorageClassName != *pvc.Spec.StorageClassName || !coversTopology(sc.NodeTopology, topology) {
+			continue
+		}
+		if sc.Capacity == nil {
+			// The driver does not know; neither do we
+			return nil
+		}
+		if sc.MaximumVolumeSize != nil && newSize.Cmp(*sc.MaximumVolumeSize) > 0 {
+			capped = sc
+			continue
+		}
+		if sc.Capacity.Cmp(needed) >= 0 {
+			return nil
+		}
+		if best == nil || sc.Capacity.Cmp(*best.Capacity) > 0 {
+			best = sc
+		}
+	}
+
+	switch {
+	case best != nil:
+		return &InsufficientError{
+			Backend:   s.Name(),
+			Needed:    needed,
+			Available: *best.Capacity,
+			Detail:    fmt.Sprintf("StorageClass %s, %s/%s", best.StorageClassName, best.Namespace, best.Name),
+		}
+	case capped != nil:
+		headroom := capped.MaximumVolumeSize.DeepCopy()
+		headroom.Sub(pvc.Status.Capacity[corev1.ResourceStorage])
+		return &InsufficientError{
+			Backend:   s.Name(),
+			Needed:    needed,
+			Available: headroom,
+			Detail:    fmt.Sprintf("maximum volume size %s", capped.MaximumVolumeSize.String()),
+		}
+	}
+	return nil
+}
+
+// volumeTopology returns the node labels the PV is pinned to by its
+// required node affinity. Only single-valued In expressions are used, which
+// is how CSI drivers express topology segments.
+func volumeTopology(pv *corev1.PersistentVolume) labels.Set {
+	if pv == nil || pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return nil
+	}
+	terms := pv.Spec.NodeAffinity.Required.NodeSelectorTerms
+	if len(terms) != 1 {
+		// Several alternative segments; any of them may hold the volume
+		return nil
+	}
+	set := labels.Set{}
+	for _, expr := range terms[0].MatchExpressions {
+		if expr.Operator == corev1.NodeSelectorOpIn && len(expr.Values) == 1 {
+			set[expr.Key] = expr.Values[0]
+		}
+	}
+	return set
+}
+
+// coversTopology reports whether a CSIStorageCapacity node topology selector
+// covers the volume's segment. Everything is covered when the segment is unknown.
+func coversTopology(selector *metav1.LabelSelector, topology labels.Set) bool {
+	if len(topology) == 0 || selector == nil {
+		return true
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return sel.Matches(topology)
+}
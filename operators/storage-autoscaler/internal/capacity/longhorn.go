@@ -0,0 +1,183 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// longhornDriver is the CSI driver name of Longhorn volumes.
+const longhornDriver = "driver.longhorn.io"
+
+// Longhorn settings that bound disk scheduling, with Longhorn's defaults.
+const (
+	settingOverProvisioning = "storage-over-provisioning-percentage"
+	settingMinimalAvailable = "storage-minimal-available-percentage"
+
+	defaultOverProvisioning = 100
+	defaultMinimalAvailable = 25
+)
+
+var longhornGroupVersion = schema.GroupVersion{Group: "longhorn.io", Version: "v1beta2"}
+
+// Longhorn checks the disks holding each replica of a Longhorn volume, using
+// the same limits Longhorn applies when scheduling a replica: the disk must
+// keep storage-minimal-available-percentage free and stay within
+// storage-over-provisioning-percentage after the growth. The Longhorn CRs are
+// read as unstructured objects, so the CRDs need not be installed for PVCs
+// of other drivers.
+type Longhorn struct {
+	// Namespace Longhorn is installed in, usually longhorn-system.
+	Namespace string
+}
+
+// Name implements Checker.
+func (Longhorn) Name() string { return "Longhorn" }
+
+// Check implements Checker.
+func (l Longhorn) Check(
+	ctx context.Context,
+	c client.Reader,
+	pvc *corev1.PersistentVolumeClaim,
+	newSize resource.Quantity,
+) error {
+	pv, err := boundVolume(ctx, c, pvc)
+	if err != nil {
+		return fmt.Errorf("getting PV %s: %w", pvc.Spec.VolumeName, err)
+	}
+	if pv == nil || pv.Spec.CSI == nil || pv.Spec.CSI.Driver != longhornDriver {
+		return nil
+	}
+	volume := pv.Spec.CSI.VolumeHandle
+
+	replicas := &unstructured.UnstructuredList{}
+	replicas.SetGroupVersionKind(longhornGroupVersion.WithKind("ReplicaList"))
+	if err := c.List(ctx, replicas, client.InNamespace(l.Namespace),
+		client.MatchingLabels{"longhornvolume": volume}); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("listing Longhorn replicas of %s: %w", volume, err)
+	}
+
+	overProvisioning := l.setting(ctx, c, settingOverProvisioning, defaultOverProvisioning)
+	minimalAvailable := l.setting(ctx, c, settingMinimalAvailable, defaultMinimalAvailable)
+	needed := growth(pvc, newSize)
+	nodes := map[string]*unstructured.Unstructured{}
+
+	for _, replica := range replicas.Items {
+		nodeID, _, _ := unstructured.NestedString(replica.Object, "spec", "nodeID")
+		diskID, _, _ := unstructured.NestedString(replica.Object, "spec", "diskID")
+		if nodeID == "" || diskID == "" {
+			continue
+		}
+		node, ok := nodes[nodeID]
+		if !ok {
+			node = &unstructured.Unstructured{}
+			node.SetGroupVersionKind(longhornGroupVersion.WithKind("Node"))
+			if err := c.Get(ctx, types.NamespacedName{Namespace: l.Namespace, Name: nodeID}, node); err != nil {
+				return fmt.Errorf("getting Longhorn node %s: %w", nodeID, err)
+			}
+			nodes[nodeID] = node
+		}
+
+		disk, found := findDisk(node, diskID)
+		if !found {
+			continue
+		}
+		available := disk.available - needed.Value()
+		minFree := disk.maximum * minimalAvailable / 100
+		schedulable := (disk.maximum-disk.reserved)*overProvisioning/100 - disk.scheduled
+		if available <= minFree || needed.Value() > schedulable {
+			free := min(disk.available-minFree, schedulable)
+			return &InsufficientError{
+				Backend:   l.Name(),
+				Needed:    needed,
+				Available: *resource.NewQuantity(max(free, 0), resource.BinarySI),
+				Detail:    fmt.Sprintf("replica on node %s disk %s", nodeID, disk.name),
+			}
+		}
+	}
+	return nil
+}
+
+// setting returns the integer value of a Longhorn setting, or def if it
+// cannot be read.
+func (l Longhorn) setting(ctx context.Context, c client.Reader, name string, def int64) int64 {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(longhornGroupVersion.WithKind("Setting"))
+	if err := c.Get(ctx, types.NamespacedName{Namespace: l.Namespace, Name: name}, obj); err != nil {
+		return def
+	}
+	raw, _, _ := unstructured.NestedString(obj.Object, "value")
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// longhornDisk holds the scheduling figures of one Longhorn disk, in bytes.
+type longhornDisk struct {
+	name      string
+	available int64
+	maximum   int64
+	scheduled int64
+	reserved  int64
+}
+
+// findDisk looks up the disk with the given UUID in a Longhorn node.
+func findDisk(node *unstructured.Unstructured, diskUUID string) (longhornDisk, bool) {
+	statuses, _, _ := unstructured.NestedMap(node.Object, "status", "diskStatus")
+	for name, raw := range statuses {
+		status, ok := raw.(map[string]any)
+		if !ok || status["diskUUID"] != diskUUID {
+			continue
+		}
+		disk := longhornDisk{
+			name:      name,
+			available: int64Field(status, "storageAvailable"),
+			maximum:   int64Field(status, "storageMaximum"),
+			scheduled: int64Field(status, "storageScheduled"),
+		}
+		spec, _, _ := unstructured.NestedMap(node.Object, "spec", "disks", name)
+		disk.reserved = int64Field(spec, "storageReserved")
+		return disk, true
+	}
+	return longhornDisk{}, false
+}
+
+// int64Field reads a numeric field decoded from JSON.
+func int64Field(m map[string]any, key string) int64 {
+	switch v := m[key].(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config loads the controller-wide manager configuration file.
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// APIVersion is the apiVersion expected in the config file.
+	APIVersion = "config.volume-autoscaler.io/v1alpha1"
+	// Kind is the kind expected in the config file.
+	Kind = "ControllerConfig"
+)
+
+// Config holds platform defaults that apply when a VolumeAutoscaler leaves
+// the corresponding spec field unset, plus global deny-lists.
+type Config struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// PrometheusURL is the Prometheus endpoint used when spec.source.prometheus.url is empty.
+	PrometheusURL string `json:"prometheusURL,omitempty"`
+
+	// ThresholdPercent is the default usage percentage that triggers expansion.
+	ThresholdPercent int32 `json:"thresholdPercent,omitempty"`
+
+	// IncreasePercent is the default percentage of capacity added per expansion.
+	IncreasePercent int32 `json:"increasePercent,omitempty"`
+
+	// EmergencyIncreasePercent is the default percentage added per emergency expansion.
+	EmergencyIncreasePercent int32 `json:"emergencyIncreasePercent,omitempty"`
+
+	// IncreaseMinimum is the default floor for a single expansion.
+	IncreaseMinimum *resource.Quantity `json:"increaseMinimum,omitempty"`
+
+	// RequeueOnError is how long to wait before retrying after a status update failure.
+	RequeueOnError *metav1.Duration `json:"requeueOnError,omitempty"`
+
+	// DenyNamespaces lists namespaces whose VolumeAutoscalers are never acted on.
+	DenyNamespaces []string `json:"denyNamespaces,omitempty"`
+
+	// DenyStorageClasses lists StorageClasses whose PVCs are never expanded.
+	DenyStorageClasses []string `json:"denyStorageClasses,omitempty"`
+
+	// StorageClassPrices maps StorageClass names to a price per GiB-month.
+	// Cost reporting is disabled when empty; PVCs of unlisted classes are
+	// reported as unpriced.
+	StorageClassPrices map[string]float64 `json:"storageClassPrices,omitempty"`
+
+	// Currency labels the amounts derived from StorageClassPrices.
+	Currency string `json:"currency,omitempty"`
+
+	// BackendCapacity selects the checks run against the storage backend's
+	// free space before a PVC is expanded.
+	BackendCapacity BackendCapacity `json:"backendCapacity,omitempty"`
+
+	// VolumeHealth selects the health sources consulted before a PVC is
+	// expanded, in addition to kubelet_volume_stats_health_abnormal.
+	VolumeHealth VolumeHealth `json:"volumeHealth,omitempty"`
+
+	// ResizeLimits bound how fast PVCs are expanded across all
+	// VolumeAutoscalers, per StorageClass or CSI driver. PVCs matching no
+	// entry are not limited. They are enforced in memory by each manager, so
+	// with --shard every shard gets the full limits.
+	ResizeLimits []ResizeLimit `json:"resizeLimits,omitempty"`
+}
+
+// BackendCapacity configures the pre-expansion backend capacity checks.
+type BackendCapacity struct {
+	// CSIStorageCapacity checks the growth against the CSIStorageCapacity
+	// objects published for the PVC's StorageClass. Enabled by default; it
+	// has no effect for drivers that do not publish capacity.
+	CSIStorageCapacity bool `json:"csiStorageCapacity"`
+
+	// LonghornNamespace, when set, checks the Longhorn disks holding the
+	// replicas of Longhorn volumes. Usually longhorn-system.
+	LonghornNamespace string `json:"longhornNamespace,omitempty"`
+}
+
+// VolumeHealth configures the pre-expansion volume health checks.
+type VolumeHealth struct {
+	// ConditionEvents blocks expansion while the PVC or its PV has a recent
+	// VolumeConditionAbnormal event from the CSI external-health-monitor.
+	// Enabled by default; it has no effect where the monitor is not deployed.
+	ConditionEvents bool `json:"conditionEvents"`
+
+	// EventWindow is how recent a VolumeConditionAbnormal event must be to
+	// count. It should exceed the monitor's check interval.
+	EventWindow *metav1.Duration `json:"eventWindow,omitempty"`
+
+	// LonghornNamespace, when set, blocks expansion of Longhorn volumes that
+	// are degraded or faulted. Usually longhorn-system.
+	LonghornNamespace string `json:"longhornNamespace,omitempty"`
+}
+
+// ResizeLimit throttles the expansions of the PVCs of one StorageClass or of
+// every StorageClass provisioned by one CSI driver. Expansions over the limit
+// wait in a queue ordered by how full each PVC is.
+type ResizeLimit struct {
+	// StorageClass selects the PVCs of this StorageClass.
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// Driver selects the PVCs whose StorageClass has this provisioner.
+	// Entries naming a StorageClass take precedence.
+	Driver string `json:"driver,omitempty"`
+
+	// PerMinute is the sustained number of PVC patches allowed per minute.
+	// Zero disables the rate limit.
+	PerMinute float64 `json:"perMinute,omitempty"`
+
+	// Burst is the number of patches allowed at once after an idle period.
+	// Defaults to 1 when PerMinute is set.
+	Burst int `json:"burst,omitempty"`
+
+	// MaxConcurrent caps the resizes that were patched but whose capacity
+	// has not grown yet. Zero means unlimited.
+	MaxConcurrent int `json:"maxConcurrent,omitempty"`
+}
+
+// Group names the queue shared by the PVCs the limit selects.
+func (l ResizeLimit) Group() string {
+	if l.StorageClass != "" {
+		return "storageClass/" + l.StorageClass
+	}
+	return "driver/" + l.Driver
+}
+
+// Default returns the built-in defaults used when no config file is given.
+func Default() *Config {
+	minimum := resource.MustParse("1Gi")
+	return &Config{
+		TypeMeta:                 metav1.TypeMeta{APIVersion: APIVersion, Kind: Kind},
+		PrometheusURL:            "http://prometheus.monitoring.svc.cluster.local:9090",
+		ThresholdPercent:         80,
+		IncreasePercent:          20,
+		EmergencyIncreasePercent: 50,
+		IncreaseMinimum:          &minimum,
+		RequeueOnError:           &metav1.Duration{Duration: 30 * time.Second},
+		BackendCapacity:          BackendCapacity{CSIStorageCapacity: true},
+		VolumeHealth: VolumeHealth{
+			ConditionEvents: true,
+			EventWindow:     &metav1.Duration{Duration: 10 * time.Minute},
+		},
+	}
+}
+
+// Load reads the config file at path and overlays it on the defaults.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes a config document and overlays it on the defaults.
+func Parse(data []byte) (*Config, error) {
+	cfg := Default()
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("decoding config: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	if c.APIVersion != APIVersion || c.Kind != Kind {
+		return fmt.Errorf("unsupported config %s/%s, expected %s/%s", c.APIVersion, c.Kind, APIVersion, Kind)
+	}
+	if c.PrometheusURL == "" {
+		return fmt.Errorf("prometheusURL must not be empty")
+	}
+	if c.ThresholdPercent < 1 || c.ThresholdPercent > 99 {
+		return fmt.Errorf("thresholdPercent must be between 1 and 99, got %d", c.ThresholdPercent)
+	}
+	if c.IncreasePercent < 1 || c.IncreasePercent > 100 {
+		return fmt.Errorf("increasePercent must be between 1 and 100, got %d", c.IncreasePercent)
+	}
+	if c.EmergencyIncreasePercent < 1 || c.EmergencyIncreasePercent > 200 {
+		return fmt.Errorf("emergencyIncreasePercent must be between 1 and 200, got %d", c.EmergencyIncreasePercent)
+	}
+	if c.IncreaseMinimum == nil || c.IncreaseMinimum.Sign() < 0 {
+		return fmt.Errorf("increaseMinimum must not be negative")
+	}
+	if c.RequeueOnError == nil || c.RequeueOnError.Duration <= 0 {
+		return fmt.Errorf("requeueOnError must be positive")
+	}
+	if c.VolumeHealth.EventWindow == nil || c.VolumeHealth.EventWindow.Duration <= 0 {
+		return fmt.Errorf("volumeHealth.eventWindow must be positive")
+	}
+	seen := map[string]bool{}
+	for i, l := range c.ResizeLimits {
+		if (l.StorageClass == "") == (l.Driver == "") {
+			return fmt.Errorf("resizeLimits[%d] must set exactly one of storageClass and driver", i)
+		}
+		if seen[l.Group()] {
+			return fmt.Errorf("resizeLimits[%d] duplicates %s", i, l.Group())
+		}
+		seen[l.Group()] = true
+		if l.PerMinute < 0 || l.Burst < 0 || l.MaxConcurrent < 0 {
+			return fmt.Errorf("resizeLimits[%d] must not have negative values", i)
+		}
+		if l.PerMinute == 0 && l.MaxConcurrent == 0 {
+			return fmt.Errorf("resizeLimits[%d] must set perMinute or maxConcurrent", i)
+		}
+		if l.PerMinute > 0 && l.Burst == 0 {
+			c.ResizeLimits[i].Burst = 1
+		}
+	}
+	for sc, price := range c.StorageClassPrices {
+		if price < 0 {
+			return fmt.Errorf("storageClassPrices[%s] must not be negative, got %v", sc, price)
+		}
+	}
+	return nil
+}
+
+// NamespaceDenied reports whether VolumeAutoscalers in ns must be ignored.
+func (c *Config) NamespaceDenied(ns string) bool {
+	return slices.Contains(c.DenyNamespaces, ns)
+}
+
+// StorageClassDenied reports whether PVCs of the StorageClass must not be expanded.
+func (c *Config) StorageClassDenied(sc string) bool {
+	return slices.Contains(c.DenyStorageClasses, sc)
+}
+
+// ResizeLimit returns the limit applying to PVCs of the StorageClass sc
+// provisioned by driver, preferring an entry for the StorageClass.
+func (c *Config) ResizeLimit(sc, driver string) (ResizeLimit, bool) {
+	var byDriver *ResizeLimit
+	for i, l := range c.ResizeLimits {
+		if l.StorageClass != "" && l.StorageClass == sc {
+			return l, true
+		}
+		if l.Driver != "" && l.Driver == driver && byDriver == nil {
+			byDriver = &c.ResizeLimits[i]
+		}
+	}
+	if byDriver == nil {
+		return ResizeLimit{}, false
+	}
+	return *byDriver, true
+}
+
+// StorageClassPrice returns the price per GiB-month of the StorageClass and
+// whether it is listed.
+func (c *Config) StorageClassPrice(sc string) (float64, bool) {
+	price, ok := c.StorageClassPrices[sc]
+	return price, ok
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParse_OverlaysDefaults(t *testing.T) {
+	cfg, err := Parse([]byte(`
+apiVersion: config.volume-autoscaler.io/v1alpha1
+kind: ControllerConfig
+thresholdPercent: 70
+increaseMinimum: 2Gi
+denyNamespaces: [kube-system]
+denyStorageClasses: [local-path]
+currency: EUR
+storageClassPrices:
+  harvester: 0.08
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ThresholdPercent != 70 {
+		t.Errorf("thresholdPercent = %d, want 70", cfg.ThresholdPercent)
+	}
+	if cfg.IncreasePercent != 20 {
+		t.Errorf("increasePercent = %d, want default 20", cfg.IncreasePercent)
+	}
+	if got := cfg.IncreaseMinimum.String(); got != "2Gi" {
+		t.Errorf("increaseMinimum = %s, want 2Gi", got)
+	}
+	if cfg.RequeueOnError.Duration != 30*time.Second {
+		t.Errorf("requeueOnError = %s, want default 30s", cfg.RequeueOnError.Duration)
+	}
+	if !cfg.NamespaceDenied("kube-system") || cfg.NamespaceDenied("default") {
+		t.Error("namespace deny-list not applied correctly")
+	}
+	if !cfg.StorageClassDenied("local-path") || cfg.StorageClassDenied("harvester") {
+		t.Error("StorageClass deny-list not applied correctly")
+	}
+	if price, ok := cfg.StorageClassPrice("harvester"); !ok || price != 0.08 {
+		t.Errorf("harvester price = %v, %v, want 0.08, true", price, ok)
+	}
+	if _, ok := cfg.StorageClassPrice("local-path"); ok {
+		t.Error("unlisted StorageClass should have no price")
+	}
+}
+
+func TestParse_BackendCapacity(t *testing.T) {
+	if cfg := Default(); !cfg.BackendCapacity.CSIStorageCapacity || cfg.BackendCapacity.LonghornNamespace != "" {
+		t.Errorf("default backendCapacity = %+v, want CSIStorageCapacity only", cfg.BackendCapacity)
+	}
+	cfg, err := Parse([]byte(`
+apiVersion: config.volume-autoscaler.io/v1alpha1
+kind: ControllerConfig
+backendCapacity:
+  longhornNamespace: longhorn-system
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.BackendCapacity.CSIStorageCapacity {
+		t.Error("csiStorageCapacity should keep its default when omitted")
+	}
+	if cfg.BackendCapacity.LonghornNamespace != "longhorn-system" {
+		t.Errorf("longhornNamespace = %q, want longhorn-system", cfg.BackendCapacity.LonghornNamespace)
+	}
+}
+
+func TestParse_VolumeHealth(t *testing.T) {
+	if cfg := Default(); !cfg.VolumeHealth.ConditionEvents || cfg.VolumeHealth.EventWindow.Duration != 10*time.Minute {
+		t.Errorf("default volumeHealth = %+v, want conditionEvents with a 10m window", cfg.VolumeHealth)
+	}
+	cfg, err := Parse([]byte(`
+apiVersion: config.volume-autoscaler.io/v1alpha1
+kind: ControllerConfig
+volumeHealth:
+  eventWindow: 3m
+  longhornNamespace: longhorn-system
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.VolumeHealth.ConditionEvents {
+		t.Error("conditionEvents should keep its default when omitted")
+	}
+	if cfg.VolumeHealth.EventWindow.Duration != 3*time.Minute || cfg.VolumeHealth.LonghornNamespace != "longhorn-system" {
+		t.Errorf("volumeHealth = %+v, want a 3m window and longhorn-system", cfg.VolumeHealth)
+	}
+}
+
+func TestParse_ResizeLimits(t *testing.T) {
+	cfg, err := Parse([]byte(`
+apiVersion: config.volume-autoscaler.io/v1alpha1
+kind: ControllerConfig
+resizeLimits:
+- driver: driver.harvesterhci.io
+  perMinute: 6
+- storageClass: harvester-ssd
+  maxConcurrent: 2
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ResizeLimits[0].Burst != 1 {
+		t.Errorf("burst = %d, want default 1", cfg.ResizeLimits[0].Burst)
+	}
+	if l, ok := cfg.ResizeLimit("harvester-ssd", "driver.harvesterhci.io"); !ok || l.Group() != "storageClass/harvester-ssd" {
+		t.Errorf("limit for harvester-ssd = %+v, %v, want the StorageClass entry", l, ok)
+	}
+	if l, ok := cfg.ResizeLimit("harvester", "driver.harvesterhci.io"); !ok || l.Group() != "driver/driver.harvesterhci.io" {
+		t.Errorf("limit for harvester = %+v, %v, want the driver entry", l, ok)
+	}
+	if _, ok := cfg.ResizeLimit("longhorn", "driver.longhorn.io"); ok {
+		t.Error("unlisted StorageClass and driver should not be limited")
+	}
+}
+
+func TestParse_RejectsInvalid(t *testing.T) {
+	tests := map[string]string{
+		"wrong kind":        "apiVersion: config.volume-autoscaler.io/v1alpha1\nkind: Other\n",
+		"threshold too big": "apiVersion: config.volume-autoscaler.io/v1alpha1\nkind: ControllerConfig\nthresholdPercent: 100\n",
+		"unknown field":     "apiVersion: config.volume-autoscaler.io/v1alpha1\nkind: ControllerConfig\nthreshold: 50\n",
+		"empty prometheus":  "apiVersion: config.volume-autoscaler.io/v1alpha1\nkind: ControllerConfig\nprometheusURL: \"\"\n",
+		"negative price":    "apiVersion: config.volume-autoscaler.io/v1alpha1\nkind: ControllerConfig\nstorageClassPrices: {harvester: -1}\n",
+		"zero event window": "apiVersion: config.volume-autoscaler.io/v1alpha1\nkind: ControllerConfig\nvolumeHealth: {eventWindow: 0s}\n",
+		"limit without key": "apiVersion: config.volume-autoscaler.io/v1alpha1\nkind: ControllerConfig\nresizeLimits: [{perMinute: 1}]\n",
+		"limit without cap": "apiVersion: config.volume-autoscaler.io/v1alpha1\nkind: ControllerConfig\nresizeLimits: [{driver: x}]\n",
+		"duplicate limit":   "apiVersion: config.volume-autoscaler.io/v1alpha1\nkind: ControllerConfig\nresizeLimits: [{driver: x, perMinute: 1}, {driver: x, maxConcurrent: 1}]\n",
+	}
+	for name, doc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(doc)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestStore_ReloadKeepsPreviousOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(doc string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("apiVersion: config.volume-autoscaler.io/v1alpha1\nkind: ControllerConfig\nthresholdPercent: 75\n")
+
+	s, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Get().ThresholdPercent != 75 {
+		t.Fatalf("thresholdPercent = %d, want 75", s.Get().ThresholdPercent)
+	}
+
+	write("apiVersion: config.volume-autoscaler.io/v1alpha1\nkind: ControllerConfig\nthresholdPercent: 85\n")
+	if err := s.Reload(); err != nil {
+		t.Fatalf("unexpected reload error: %v", err)
+	}
+	if s.Get().ThresholdPercent != 85 {
+		t.Errorf("thresholdPercent = %d, want 85", s.Get().ThresholdPercent)
+	}
+
+	write("not: [valid")
+	if err := s.Reload(); err == nil {
+		t.Error("expected reload error for invalid file")
+	}
+	if s.Get().ThresholdPercent != 85 {
+		t.Errorf("thresholdPercent = %d, want previous value 85", s.Get().ThresholdPercent)
+	}
+}
+
+func TestNewStore_EmptyPathUsesDefaults(t *testing.T) {
+	s, err := NewStore("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Get().ThresholdPercent != 80 {
+		t.Errorf("thresholdPercent = %d, want 80", s.Get().ThresholdPercent)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Store holds the active Config and reloads it when the file changes.
+// It implements manager.Runnable so the watch runs for the manager's lifetime.
+type Store struct {
+	path string
+
+	mu  sync.RWMutex
+	cfg *Config
+}
+
+// NewStore loads the config file at path. An empty path yields a Store
+// that always serves Default() and never reloads.
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path, cfg: Default()}
+	if path == "" {
+		return s, nil
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	s.cfg = cfg
+	return s, nil
+}
+
+// NewStaticStore returns a Store that always serves cfg and never reloads.
+func NewStaticStore(cfg *Config) *Store {
+	return &Store{cfg: cfg}
+}
+
+// Get returns the current config. Callers must not modify it.
+func (s *Store) Get() *Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// Reload re-reads the config file. On error the previous config stays active.
+func (s *Store) Reload() error {
+	if s.path == "" {
+		return nil
+	}
+	cfg, err := Load(s.path)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.cfg = cfg
+	s.mu.Unlock()
+	return nil
+}
+
+// NeedLeaderElection returns false so every replica reloads its config.
+func (s *Store) NeedLeaderElection() bool {
+	return false
+}
+
+// Start watches the config file's directory until ctx is cancelled.
+// The directory is watched rather than the file because ConfigMap volumes
+// swap a symlink on update instead of writing the file in place.
+func (s *Store) Start(ctx context.Context) error {
+	if s.path == "" {
+		<-ctx.Done()
+		return nil
+	}
+	log := logf.FromContext(ctx).WithName("config").WithValues("path", s.path)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating config watcher: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+	if err := watcher.Add(filepath.Dir(s.path)); err != nil {
+		return fmt.Errorf("watching config directory: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			if err := s.Reload(); err != nil {
+				log.Error(err, "failed to reload config, keeping previous values")
+				continue
+			}
+			log.Info("reloaded controller config")
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Error(err, "config watcher error")
+		}
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+	appmetrics "github.com/volume-autoscaler/volume-autoscaler/internal/metrics"
+	promclient "github.com/volume-autoscaler/volume-autoscaler/internal/prometheus"
+)
+
+// AnnotationAnomalyAck on a PVC acknowledges its current growth anomaly.
+// The controller clears the anomaly, pauses detection for anomalySnooze and
+// removes the annotation.
+const AnnotationAnomalyAck = "volume-autoscaler.io/anomaly-ack"
+
+// Values of PVCAnomaly.Kind and the kind label on AnomaliesTotal.
+const (
+	anomalyKindJump          = "Jump"
+	anomalyKindGrowthRate    = "GrowthRate"
+	anomalyKindExpansionRate = "ExpansionRate"
+	anomalyKindMaxSize       = "MaxSizeProjected"
+)
+
+const (
+	// anomalySnooze is how long detection stays paused after an acknowledgement.
+	anomalySnooze = 24 * time.Hour
+	// expansionRateWindow is the window maxExpansionsPerDay is counted over,
+	// and the growth projectMaxSize extrapolates over the same span ahead.
+	expansionRateWindow = 24 * time.Hour
+
+	defaultRateWindow     = time.Hour
+	defaultBaselineWindow = 7 * 24 * time.Hour
+)
+
+// checkAnomalies handles acknowledgements and runs the configured anomaly
+// checks for one PVC, recording the result in pvcStatus. prev is the PVC's
+// status from the previous poll, or nil. It reports whether expansion of the
+// PVC is suppressed. An acknowledgement is consumed even with detection
+// disabled, so the annotation does not linger on the PVC.
+func (r *VolumeAutoscalerReconciler) checkAnomalies(
+	ctx context.Context,
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	pvc *corev1.PersistentVolumeClaim,
+	prev, pvcStatus *autoscalingv1beta1.PVCStatus,
+	capBytes float64,
+	prom *promclient.Client,
+) bool {
+	log := logf.FromContext(ctx).WithValues("pvc", pvc.Name)
+	now := r.now()
+	if _, ok := pvc.Annotations[AnnotationAnomalyAck]; ok {
+		if pvcStatus.Anomaly != nil {
+			r.Recorder.Eventf(va, nil, corev1.EventTypeNormal, "AnomalyAcknowledged", "DetectAnomaly",
+				"%s anomaly on PVC %s/%s acknowledged", pvcStatus.Anomaly.Kind, pvc.Namespace, pvc.Name)
+		}
+		pvcStatus.Anomaly = nil
+		pvcStatus.AnomalyAcknowledgedTime = &now
+		patch := client.MergeFrom(pvc.DeepCopy())
+		delete(pvc.Annotations, AnnotationAnomalyAck)
+		if err := r.Patch(ctx, pvc, patch); err != nil {
+			log.Error(err, "failed to clear anomaly-ack annotation")
+		}
+	}
+
+	ad := va.Spec.AnomalyDetection
+	if ad == nil {
+		pvcStatus.Anomaly = nil
+		appmetrics.PVCAnomalous.DeleteLabelValues(pvc.Namespace, pvc.Name, va.Name)
+		return false
+	}
+
+	snoozed := pvcStatus.AnomalyAcknowledgedTime != nil &&
+		now.Sub(pvcStatus.AnomalyAcknowledgedTime.Time) < anomalySnooze
+	if pvcStatus.Anomaly == nil && !snoozed {
+		if kind, msg := r.detectAnomaly(ctx, va, pvc, prev, pvcStatus, capBytes, prom); kind != "" {
+			pvcStatus.Anomaly = &autoscalingv1beta1.PVCAnomaly{Kind: kind, Message: msg, DetectedTime: now}
+			if ad.SuppressExpansion {
+				msg += "; expansion suppressed until acknowledged"
+			}
+			log.Info("anomalous growth detected", "kind", kind, "detail", msg)
+			r.Recorder.Eventf(va, nil, corev1.EventTypeWarning, "AnomalousGrowth", "DetectAnomaly",
+				"PVC %s/%s: %s", pvc.Namespace, pvc.Name, msg)
+			appmetrics.AnomaliesTotal.WithLabelValues(pvc.Namespace, pvc.Name, va.Name, kind).Inc()
+		}
+	}
+
+	anomalous := 0.0
+	if pvcStatus.Anomaly != nil {
+		anomalous = 1
+	}
+	appmetrics.PVCAnomalous.WithLabelValues(pvc.Namespace, pvc.Name, va.Name).Set(anomalous)
+
+	return ad.SuppressExpansion && pvcStatus.Anomaly != nil
+}
+
+// detectAnomaly runs each enabled check in turn and returns the kind and a
+// description of the first one that fires, or an empty kind.
+func (r *VolumeAutoscalerReconciler) detectAnomaly(
+	ctx context.Context,
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	pvc *corev1.PersistentVolumeClaim,
+	prev, pvcStatus *autoscalingv1beta1.PVCStatus,
+	capBytes float64,
+	prom *promclient.Client,
+) (string, string) {
+	ad := va.Spec.AnomalyDetection
+	if ad.MaxJumpPercent > 0 && prev != nil && prev.UsageBytes > 0 {
+		jump := float64(pvcStatus.UsageBytes-prev.UsageBytes) / capBytes * 100
+		if jump > float64(ad.MaxJumpPercent) {
+			return anomalyKindJump, fmt.Sprintf("usage grew by %.0f%% of capacity in one poll (limit %d%%)",
+				jump, ad.MaxJumpPercent)
+		}
+	}
+
+	if ad.MaxExpansionsPerDay > 0 {
+		since := r.now().Add(-expansionRateWindow)
+		count := int32(0)
+		for _, e := range pvcStatus.RecentExpansions {
+			if e.Time.After(since) {
+				count++
+			}
+		}
+		if count > ad.MaxExpansionsPerDay {
+			return anomalyKindExpansionRate, fmt.Sprintf("expanded %d times in 24h (limit %d)",
+				count, ad.MaxExpansionsPerDay)
+		}
+	}
+
+	if ad.ProjectMaxSize {
+		if msg := r.projectMaxSize(va, pvc, pvcStatus); msg != "" {
+			return anomalyKindMaxSize, msg
+		}
+	}
+
+	if ad.GrowthRateFactor > 0 {
+		rateWindow, baselineWindow := defaultRateWindow, defaultBaselineWindow
+		if ad.RateWindow != nil {
+			rateWindow = ad.RateWindow.Duration
+		}
+		if ad.BaselineWindow != nil {
+			baselineWindow = ad.BaselineWindow.Duration
+		}
+		rate, err1 := prom.Query(ctx, growthRateQuery(pvc, rateWindow))
+		baseline, err2 := prom.Query(ctx, growthRateQuery(pvc, baselineWindow))
+		if err := errors.Join(err1, err2); err != nil {
+			// Not enough history yet is common for new PVCs; skip the check
+			logf.FromContext(ctx).V(1).Info("growth rate query failed, skipping check", "pvc", pvc.Name, "error", err.Error())
+			return "", ""
+		}
+		if baseline > 0 && rate > baseline*float64(ad.GrowthRateFactor) {
+			return anomalyKindGrowthRate, fmt.Sprintf("growing at %.0fx its %s baseline over the last %s (limit %dx)",
+				rate/baseline, baselineWindow, rateWindow, ad.GrowthRateFactor)
+		}
+	}
+
+	return "", ""
+}
+
+// projectMaxSize extrapolates the capacity added to the PVC over the last
+// expansionRateWindow over the next one. It returns a description if the PVC
+// was expanded more than once in the window and the projected size reaches
+// its maxSize, or an empty string.
+func (r *VolumeAutoscalerReconciler) projectMaxSize(
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	pvc *corev1.PersistentVolumeClaim,
+	pvcStatus *autoscalingv1beta1.PVCStatus,
+) string {
+	since := r.now().Add(-expansionRateWindow)
+	count := 0
+	current := pvcStatus.CurrentSize.Value()
+	var added int64
+	for _, e := range pvcStatus.RecentExpansions {
+		if !e.Time.After(since) {
+			continue
+		}
+		count++
+		added += e.To.Value() - e.From.Value()
+		// The resize may not be reflected in the PVC's capacity yet
+		current = max(current, e.To.Value())
+	}
+	if count < 2 || added <= 0 {
+		return ""
+	}
+
+	maxSize := va.Spec.Limits.MaxSize
+	if pvcVA, err := withPVCOverrides(va, pvc); err == nil {
+		maxSize = pvcVA.Spec.Limits.MaxSize
+	}
+	projected := resource.NewQuantity(current+added, resource.BinarySI)
+	if projected.Cmp(maxSize) < 0 {
+		return ""
+	}
+	return fmt.Sprintf("grew by %s in %d expansions in 24h; at that rate it reaches maxSize %s within a day",
+		resource.NewQuantity(added, resource.BinarySI), count, maxSize.String())
+}
+
+// growthRateQuery returns the PromQL for the PVC's growth in bytes per second
+// over window.
+func growthRateQuery(pvc *corev1.PersistentVolumeClaim, window time.Duration) string {
+	return fmt.Sprintf(
+		`deriv(kubelet_volume_stats_used_bytes{namespace="%s",persistentvolumeclaim="%s"}[%ds])`,
+		pvc.Namespace, pvc.Name, int64(window.Seconds()),
+	)
+}
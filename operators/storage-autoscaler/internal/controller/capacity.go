@@ -0,0 +1,117 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/capacity"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/config"
+	appmetrics "github.com/volume-autoscaler/volume-autoscaler/internal/metrics"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/tracing"
+)
+
+// conditionInsufficientBackendCapacity is True while the storage backend
+// lacks room for at least one pending expansion.
+const conditionInsufficientBackendCapacity = "InsufficientBackendCapacity"
+
+// capacityCheckers returns the backend capacity checks to run before an
+// expansion: r.CapacityCheckers if set, otherwise those enabled in cfg.
+func (r *VolumeAutoscalerReconciler) capacityCheckers(cfg *config.Config) []capacity.Checker {
+	if r.CapacityCheckers != nil {
+		return r.CapacityCheckers
+	}
+	return capacity.FromConfig(cfg.BackendCapacity)
+}
+
+// checkBackendCapacity returns an *capacity.InsufficientError when the
+// backend has no room to grow the PVC to newSize. A backend that cannot be
+// queried does not block the expansion; the CSI driver remains the final
+// judge.
+func (r *VolumeAutoscalerReconciler) checkBackendCapacity(
+	ctx context.Context,
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	pvc *corev1.PersistentVolumeClaim,
+	newSize resource.Quantity,
+	cfg *config.Config,
+) error {
+	ctx, span := tracer.Start(ctx, "CheckBackendCapacity", trace.WithAttributes(attribute.String("pvc", pvc.Name)))
+	err := capacity.Check(ctx, r.Client, r.capacityCheckers(cfg), pvc, newSize)
+	var short *capacity.InsufficientError
+	if err == nil || errors.As(err, &short) {
+		if err != nil {
+			span.SetAttributes(attribute.String("blocked", err.Error()))
+		}
+		span.End()
+		return err
+	}
+	tracing.End(span, err)
+	logf.FromContext(ctx).Error(err, "backend capacity check failed, expanding anyway", "pvc", pvc.Name)
+	appmetrics.PollErrorsTotal.WithLabelValues(va.Namespace, va.Name, "backend_capacity").Inc()
+	return nil
+}
+
+// setInsufficientCapacity records the PVCs whose expansion the backend has
+// no room for in the InsufficientBackendCapacity condition and the per-PVC
+// gauge, and emits an event when the condition first turns True. The
+// condition is only added once a shortage has been seen.
+func (r *VolumeAutoscalerReconciler) setInsufficientCapacity(
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	pvcs []corev1.PersistentVolumeClaim,
+	short map[string]string,
+) {
+	for _, pvc := range pvcs {
+		insufficient := 0.0
+		if _, ok := short[pvc.Name]; ok {
+			insufficient = 1
+		}
+		appmetrics.BackendCapacityInsufficient.WithLabelValues(va.Namespace, pvc.Name, va.Name).Set(insufficient)
+	}
+
+	if len(short) == 0 {
+		if meta.FindStatusCondition(va.Status.Conditions, conditionInsufficientBackendCapacity) != nil {
+			r.setConditionType(va, conditionInsufficientBackendCapacity, metav1.ConditionFalse,
+				"CapacityAvailable", "the storage backend has room for pending expansions")
+		}
+		return
+	}
+	details := make([]string, 0, len(short))
+	for _, pvc := range pvcs {
+		if reason, ok := short[pvc.Name]; ok {
+			details = append(details, fmt.Sprintf("%s: %s", pvc.Name, reason))
+		}
+	}
+	message := "expansion refused for lack of backend capacity: " + strings.Join(details, "; ")
+	if !meta.IsStatusConditionTrue(va.Status.Conditions, conditionInsufficientBackendCapacity) {
+		r.Recorder.Eventf(va, nil, corev1.EventTypeWarning, "InsufficientBackendCapacity", "CheckExpansion",
+			"%s", message)
+	}
+	r.setConditionType(va, conditionInsufficientBackendCapacity, metav1.ConditionTrue,
+		"InsufficientBackendCapacity", message)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/config"
+	appmetrics "github.com/volume-autoscaler/volume-autoscaler/internal/metrics"
+)
+
+const (
+	// expansionHistoryWindow is how far back recentExpansions reaches.
+	expansionHistoryWindow = 30 * 24 * time.Hour
+	// maxExpansionHistory bounds recentExpansions per PVC; it matches the
+	// MaxItems marker on the field.
+	maxExpansionHistory = 200
+
+	bytesPerGiB = 1 << 30
+)
+
+// recentExpansions returns the expansions within expansionHistoryWindow of
+// now, keeping at most the newest maxExpansionHistory entries.
+func recentExpansions(expansions []autoscalingv1beta1.PVCExpansion, now time.Time) []autoscalingv1beta1.PVCExpansion {
+	var out []autoscalingv1beta1.PVCExpansion
+	for _, e := range expansions {
+		if now.Sub(e.Time.Time) < expansionHistoryWindow {
+			out = append(out, e)
+		}
+	}
+	if len(out) > maxExpansionHistory-1 {
+		// Leave room for the expansion about to be recorded
+		out = out[len(out)-(maxExpansionHistory-1):]
+	}
+	return out
+}
+
+// updateCost prices the targeted PVCs with the StorageClass price table and
+// records the result on the status and the cost gauges. Cost reporting is
+// off, and any previous figures are cleared, when the table is empty.
+func updateCost(va *autoscalingv1beta1.VolumeAutoscaler, pvcs []corev1.PersistentVolumeClaim, cfg *config.Config) {
+	if len(cfg.StorageClassPrices) == 0 {
+		va.Status.Cost = nil
+		appmetrics.ProvisionedCostMonthly.DeleteLabelValues(va.Namespace, va.Name)
+		appmetrics.AutoscaledCostMonthly30d.DeleteLabelValues(va.Namespace, va.Name)
+		return
+	}
+
+	history := make(map[string][]autoscalingv1beta1.PVCExpansion, len(va.Status.PVCs))
+	for _, st := range va.Status.PVCs {
+		history[st.Name] = st.RecentExpansions
+	}
+
+	var provisioned, added float64
+	var unpriced int32
+	for _, pvc := range pvcs {
+		sc := ""
+		if pvc.Spec.StorageClassName != nil {
+			sc = *pvc.Spec.StorageClassName
+		}
+		price, ok := cfg.StorageClassPrice(sc)
+		if !ok {
+			unpriced++
+			continue
+		}
+		capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+		provisioned += gib(capacity) * price
+		for _, e := range history[pvc.Name] {
+			added += (gib(e.To) - gib(e.From)) * price
+		}
+	}
+
+	va.Status.Cost = &autoscalingv1beta1.CostStatus{
+		Currency:               cfg.Currency,
+		ProvisionedMonthly:     fmt.Sprintf("%.2f", provisioned),
+		AddedLast30DaysMonthly: fmt.Sprintf("%.2f", added),
+		UnpricedPVCs:           unpriced,
+	}
+	appmetrics.ProvisionedCostMonthly.WithLabelValues(va.Namespace, va.Name).Set(provisioned)
+	appmetrics.AutoscaledCostMonthly30d.WithLabelValues(va.Namespace, va.Name).Set(added)
+}
+
+func gib(q resource.Quantity) float64 {
+	return float64(q.Value()) / bytesPerGiB
+}
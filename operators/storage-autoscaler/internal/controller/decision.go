@@ -0,0 +1,85 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/decision"
+)
+
+// Names of the checks in a decision record, in the order they run.
+const (
+	CheckAnomaly         = "anomaly"
+	CheckOverride        = "override"
+	CheckCooldown        = "cooldown"
+	CheckSafety          = "safety"
+	CheckHealth          = "health"
+	CheckBackendCapacity = "backendCapacity"
+	CheckResizeLimit     = "resizeLimit"
+)
+
+// newDecision starts the decision record for one PVC.
+func newDecision(va *autoscalingv1beta1.VolumeAutoscaler, pvc *corev1.PersistentVolumeClaim, t time.Time) *decision.Record {
+	return &decision.Record{
+		Time:             t,
+		Namespace:        pvc.Namespace,
+		PVC:              pvc.Name,
+		VolumeAutoscaler: va.Name,
+	}
+}
+
+// decisionInputs captures what a PVC's decision is based on.
+func decisionInputs(
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	status *autoscalingv1beta1.PVCStatus,
+	capBytes float64,
+	threshold int32,
+) decision.Inputs {
+	in := decision.Inputs{
+		UsageBytes:                status.UsageBytes,
+		CapacityBytes:             int64(capBytes),
+		FreeBytes:                 status.FreeBytes,
+		UsagePercent:              status.UsagePercent,
+		CurrentSize:               status.CurrentSize.String(),
+		ThresholdPercent:          threshold,
+		EmergencyThresholdPercent: va.Spec.Trigger.EmergencyThresholdPercent,
+		MaxSize:                   va.Spec.Limits.MaxSize.String(),
+	}
+	if va.Spec.Trigger.MinFreeBytes != nil {
+		in.MinFreeBytes = va.Spec.Trigger.MinFreeBytes.String()
+		in.Mode = string(autoscalingv1beta1.ThresholdModeAny)
+		if va.Spec.Trigger.Mode != "" {
+			in.Mode = string(va.Spec.Trigger.Mode)
+		}
+	}
+	return in
+}
+
+// decide completes rec with the action taken and adds it to the decision
+// log, if one is configured.
+func (r *VolumeAutoscalerReconciler) decide(rec *decision.Record, action, reason string) {
+	if r.Decisions == nil {
+		return
+	}
+	rec.Action = action
+	rec.Reason = reason
+	r.Decisions.Add(*rec)
+}
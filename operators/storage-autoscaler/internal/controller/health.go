@@ -0,0 +1,115 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/config"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/health"
+	appmetrics "github.com/volume-autoscaler/volume-autoscaler/internal/metrics"
+	promclient "github.com/volume-autoscaler/volume-autoscaler/internal/prometheus"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/tracing"
+)
+
+// conditionVolumeUnhealthy is True while at least one pending expansion is
+// blocked because the volume is reported unhealthy.
+const conditionVolumeUnhealthy = "VolumeUnhealthy"
+
+// healthCheckers returns the volume health checks to run before an
+// expansion: r.HealthCheckers if set, otherwise the kubelet metric on prom
+// followed by the sources enabled in cfg.
+func (r *VolumeAutoscalerReconciler) healthCheckers(cfg *config.Config, prom *promclient.Client) []health.Checker {
+	if r.HealthCheckers != nil {
+		return r.HealthCheckers
+	}
+	return append([]health.Checker{health.Kubelet{Prometheus: prom}}, health.FromConfig(cfg.VolumeHealth)...)
+}
+
+// checkVolumeHealth returns the report of the first health source that
+// considers the PVC's volume unhealthy, or nil. Sources that cannot be
+// queried do not block the expansion; their error is logged, counted and
+// returned as the second value so the decision record can show the gap.
+func (r *VolumeAutoscalerReconciler) checkVolumeHealth(
+	ctx context.Context,
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	pvc *corev1.PersistentVolumeClaim,
+	checkers []health.Checker,
+) (*health.UnhealthyError, error) {
+	ctx, span := tracer.Start(ctx, "CheckVolumeHealth", trace.WithAttributes(attribute.String("pvc", pvc.Name)))
+	err := health.Check(ctx, r.Client, checkers, pvc)
+	var unhealthy *health.UnhealthyError
+	if err == nil || errors.As(err, &unhealthy) {
+		if unhealthy != nil {
+			span.SetAttributes(attribute.String("blocked", unhealthy.Error()))
+		}
+		span.End()
+		return unhealthy, nil
+	}
+	tracing.End(span, err)
+	logf.FromContext(ctx).Error(err, "volume health could not be fully checked, expanding anyway", "pvc", pvc.Name)
+	appmetrics.PollErrorsTotal.WithLabelValues(va.Namespace, va.Name, "volume_health").Inc()
+	return nil, err
+}
+
+// setVolumeUnhealthy records the PVCs whose expansion was blocked by an
+// unhealthy volume in the VolumeUnhealthy condition and the per-PVC gauge,
+// and emits an event when the condition first turns True. The condition is
+// only added once an unhealthy volume has been seen.
+func (r *VolumeAutoscalerReconciler) setVolumeUnhealthy(
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	pvcs []corev1.PersistentVolumeClaim,
+	unhealthy map[string]string,
+) {
+	for _, pvc := range pvcs {
+		value := 0.0
+		if _, ok := unhealthy[pvc.Name]; ok {
+			value = 1
+		}
+		appmetrics.PVCUnhealthy.WithLabelValues(va.Namespace, pvc.Name, va.Name).Set(value)
+	}
+
+	if len(unhealthy) == 0 {
+		if meta.FindStatusCondition(va.Status.Conditions, conditionVolumeUnhealthy) != nil {
+			r.setConditionType(va, conditionVolumeUnhealthy, metav1.ConditionFalse,
+				"VolumesHealthy", "no pending expansion is blocked by volume health")
+		}
+		return
+	}
+	details := make([]string, 0, len(unhealthy))
+	for _, pvc := range pvcs {
+		if reason, ok := unhealthy[pvc.Name]; ok {
+			details = append(details, fmt.Sprintf("%s: %s", pvc.Name, reason))
+		}
+	}
+	message := "expansion blocked by unhealthy volume: " + strings.Join(details, "; ")
+	if !meta.IsStatusConditionTrue(va.Status.Conditions, conditionVolumeUnhealthy) {
+		r.Recorder.Eventf(va, nil, corev1.EventTypeWarning, "VolumeUnhealthy", "CheckHealth", "%s", message)
+	}
+	r.setConditionType(va, conditionVolumeUnhealthy, metav1.ConditionTrue, "VolumeUnhealthy", message)
+}
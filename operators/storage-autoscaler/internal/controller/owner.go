@@ -0,0 +1,183 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+)
+
+// conditionOwnerReverted is True while the owner of a targeted PVC has
+// reverted an expansion.
+const conditionOwnerReverted = "OwnerReverted"
+
+// resolveOwnedPVCs returns the PVCs in the VolumeAutoscaler's namespace that
+// belong to the owner in spec.target.ownerRef: PVCs it owns directly, PVCs
+// owned by one of its Pods, and PVCs created from a StatefulSet's
+// volumeClaimTemplates (which carry no owner reference by default).
+func (r *VolumeAutoscalerReconciler) resolveOwnedPVCs(
+	ctx context.Context,
+	va *autoscalingv1beta1.VolumeAutoscaler,
+) ([]corev1.PersistentVolumeClaim, error) {
+	ref := va.Spec.Target.OwnerRef
+
+	var pvcList corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &pvcList, client.InNamespace(va.Namespace)); err != nil {
+		return nil, err
+	}
+
+	var templatePrefixes []string
+	if ref.Kind == "StatefulSet" && groupMatches(ref.APIVersion, appsv1.SchemeGroupVersion.String()) {
+		var sts appsv1.StatefulSet
+		err := r.Get(ctx, types.NamespacedName{Namespace: va.Namespace, Name: ref.Name}, &sts)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("getting StatefulSet %s: %w", ref.Name, err)
+		}
+		for _, tmpl := range sts.Spec.VolumeClaimTemplates {
+			templatePrefixes = append(templatePrefixes, tmpl.Name+"-"+sts.Name+"-")
+		}
+	}
+
+	// Pods are only listed when a PVC is owned by one
+	var ownedPods map[string]bool
+	podOwned := func(pvc *corev1.PersistentVolumeClaim) (bool, error) {
+		for _, owner := range pvc.OwnerReferences {
+			if owner.Kind != "Pod" || owner.APIVersion != "v1" {
+				continue
+			}
+			if ownedPods == nil {
+				var pods corev1.PodList
+				if err := r.List(ctx, &pods, client.InNamespace(va.Namespace)); err != nil {
+					return false, fmt.Errorf("listing pods: %w", err)
+				}
+				ownedPods = make(map[string]bool)
+				for _, pod := range pods.Items {
+					if ownedBy(pod.OwnerReferences, ref) {
+						ownedPods[pod.Name] = true
+					}
+				}
+			}
+			if ownedPods[owner.Name] {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	var pvcs []corev1.PersistentVolumeClaim
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if excluded(pvc) {
+			continue
+		}
+		match := ownedBy(pvc.OwnerReferences, ref) || fromTemplate(pvc.Name, templatePrefixes)
+		if !match {
+			var err error
+			if match, err = podOwned(pvc); err != nil {
+				return nil, err
+			}
+		}
+		if match {
+			pvcs = append(pvcs, *pvc)
+		}
+	}
+	return pvcs, nil
+}
+
+// ownedBy reports whether any of refs points at the owner.
+func ownedBy(refs []metav1.OwnerReference, owner *autoscalingv1beta1.VolumeAutoscalerOwnerRef) bool {
+	for _, ref := range refs {
+		if ref.Kind == owner.Kind && ref.Name == owner.Name && groupMatches(owner.APIVersion, ref.APIVersion) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupMatches compares the API groups of two apiVersions. An empty want
+// matches any group.
+func groupMatches(want, got string) bool {
+	if want == "" {
+		return true
+	}
+	wantGV, err1 := schema.ParseGroupVersion(want)
+	gotGV, err2 := schema.ParseGroupVersion(got)
+	return err1 == nil && err2 == nil && wantGV.Group == gotGV.Group
+}
+
+// fromTemplate reports whether name is <prefix><ordinal> for one of the
+// StatefulSet template prefixes.
+func fromTemplate(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		ordinal, ok := strings.CutPrefix(name, prefix)
+		if ok && ordinal != "" && strings.Trim(ordinal, "0123456789") == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ownerReverted reports whether the PVC's requested size is below the size
+// the controller last set, meaning another controller (normally the PVC's
+// owner) has reverted the expansion. It returns the current request.
+func ownerReverted(pvc *corev1.PersistentVolumeClaim, prev *autoscalingv1beta1.PVCStatus) (resource.Quantity, bool) {
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if prev == nil || prev.LastScaleSize == nil {
+		return requested, false
+	}
+	return requested, requested.Cmp(*prev.LastScaleSize) < 0
+}
+
+// ownerName describes the first owner of the PVC for messages.
+func ownerName(pvc *corev1.PersistentVolumeClaim) string {
+	if len(pvc.OwnerReferences) == 0 {
+		return "another controller"
+	}
+	return pvc.OwnerReferences[0].Kind + "/" + pvc.OwnerReferences[0].Name
+}
+
+// setOwnerReverted records the reverted PVCs in the OwnerReverted condition
+// and emits an event when the condition first turns True. The condition is
+// only added once a reversion has been seen.
+func (r *VolumeAutoscalerReconciler) setOwnerReverted(va *autoscalingv1beta1.VolumeAutoscaler, reverts []string) {
+	if len(reverts) == 0 {
+		if meta.FindStatusCondition(va.Status.Conditions, conditionOwnerReverted) != nil {
+			r.setConditionType(va, conditionOwnerReverted, metav1.ConditionFalse, "NoReversion",
+				"no expansions have been reverted")
+		}
+		return
+	}
+	message := fmt.Sprintf("expansion paused for PVCs reverted by their owner: %s; raise the size on the owner",
+		strings.Join(reverts, ", "))
+	if !meta.IsStatusConditionTrue(va.Status.Conditions, conditionOwnerReverted) {
+		r.Recorder.Eventf(va, nil, corev1.EventTypeWarning, "OwnerReverted", "CheckExpansion", "%s", message)
+	}
+	r.setConditionType(va, conditionOwnerReverted, metav1.ConditionTrue, "SizeReverted", message)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/capacity"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/config"
+	appmetrics "github.com/volume-autoscaler/volume-autoscaler/internal/metrics"
+)
+
+// ExpansionPlan describes what the controller would do for a PVC on its next poll.
+type ExpansionPlan struct {
+	// PVC is the claim name.
+	PVC string
+	// CurrentSize is the capacity reported in the PVC status.
+	CurrentSize resource.Quantity
+	// UsagePercent is the usage the plan was evaluated at.
+	UsagePercent int32
+	// ThresholdPercent is the effective expansion threshold.
+	ThresholdPercent int32
+	// Mode is one of the appmetrics.ScaleMode* values, or empty if no
+	// expansion would be attempted.
+	Mode string
+	// TriggeredBy lists the criteria that call for the expansion, as in
+	// PVCStatus.TriggeredBy.
+	TriggeredBy []string
+	// NewSize is the size the PVC would be patched to. Only set when Blocked is empty.
+	NewSize *resource.Quantity
+	// Blocked is the safety check that would prevent the expansion, if any.
+	Blocked string
+}
+
+// PlanExpansion runs the threshold logic, safety checks and size calculation
+// the reconciler uses, without patching anything or emitting events; c is
+// only read from. The health and inode checks are skipped because they need
+// Prometheus, and a backend capacity check that cannot be queried is ignored.
+// status carries the PVC's last scale time and free space, and cfg may be nil
+// to use the built-in defaults.
+func PlanExpansion(
+	ctx context.Context,
+	c client.Client,
+	cfg *config.Config,
+	clk clock.PassiveClock,
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	pvc *corev1.PersistentVolumeClaim,
+	status autoscalingv1beta1.PVCStatus,
+	usagePercent int32,
+) ExpansionPlan {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	r := &VolumeAutoscalerReconciler{
+		Client:   c,
+		Recorder: &events.FakeRecorder{},
+		Config:   config.NewStaticStore(cfg),
+		Clock:    clk,
+	}
+
+	currentSize := pvc.Status.Capacity[corev1.ResourceStorage]
+	plan := ExpansionPlan{
+		PVC:              pvc.Name,
+		CurrentSize:      currentSize,
+		UsagePercent:     usagePercent,
+		ThresholdPercent: va.Spec.Trigger.ThresholdPercent,
+	}
+	if plan.ThresholdPercent == 0 {
+		plan.ThresholdPercent = cfg.ThresholdPercent
+	}
+
+	applyScaleRecord(pvc, &status)
+	freeBytes, freeKnown := freeBytesAt(status, usagePercent)
+	plan.TriggeredBy = thresholdTriggers(va, plan.ThresholdPercent, usagePercent, freeBytes, freeKnown)
+	emergency := va.Spec.Trigger.EmergencyThresholdPercent > 0 && usagePercent >= va.Spec.Trigger.EmergencyThresholdPercent
+	if emergency {
+		plan.TriggeredBy = append(plan.TriggeredBy, TriggerEmergency)
+	}
+	forced := pvc.Annotations[AnnotationExpandNow] != ""
+	if forced {
+		plan.TriggeredBy = append(plan.TriggeredBy, TriggerExpandNow)
+	}
+
+	cooldown := CooldownFor(va)
+	switch {
+	case emergency:
+		plan.Mode = appmetrics.ScaleModeEmergency
+		cooldown = 0
+	case forced:
+		plan.Mode = appmetrics.ScaleModeForced
+		cooldown = 0
+	case len(plan.TriggeredBy) > 0:
+		plan.Mode = appmetrics.ScaleModeNormal
+	default:
+		return plan
+	}
+
+	if ad := va.Spec.AnomalyDetection; ad != nil && ad.SuppressExpansion && status.Anomaly != nil &&
+		plan.Mode != appmetrics.ScaleModeForced {
+		plan.Blocked = fmt.Sprintf("%s anomaly awaiting acknowledgement", status.Anomaly.Kind)
+		return plan
+	}
+
+	va, err := withPVCOverrides(va, pvc)
+	if err != nil {
+		plan.Blocked = err.Error()
+		return plan
+	}
+	if err := checkCooldown(&status, cooldown, r.now().Time); err != nil {
+		plan.Blocked = err.Error()
+		return plan
+	}
+	if err := r.safetyChecks(ctx, va, pvc); err != nil {
+		plan.Blocked = err.Error()
+		return plan
+	}
+
+	var newSize resource.Quantity
+	if plan.Mode == appmetrics.ScaleModeEmergency {
+		newSize = r.calculateEmergencySize(va, &currentSize)
+	} else {
+		newSize = r.calculateNewSize(va, &currentSize)
+	}
+	if err := capacity.Check(ctx, c, r.capacityCheckers(cfg), pvc, newSize); err != nil {
+		var short *capacity.InsufficientError
+		if errors.As(err, &short) {
+			plan.Blocked = err.Error()
+			return plan
+		}
+	}
+	plan.NewSize = &newSize
+	return plan
+}
+
+// freeBytesAt returns the free space recorded in status, rescaled when the
+// plan is evaluated at a different usage than status was measured at. It is
+// unknown for a status written before freeBytes was recorded.
+func freeBytesAt(status autoscalingv1beta1.PVCStatus, usagePercent int32) (int64, bool) {
+	if status.FreeBytes == 0 && status.UsagePercent < 100 {
+		return 0, false
+	}
+	if usagePercent == status.UsagePercent {
+		return status.FreeBytes, true
+	}
+	total := status.UsageBytes + status.FreeBytes
+	return total - total*int64(usagePercent)/100, true
+}
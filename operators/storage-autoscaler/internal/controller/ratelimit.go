@@ -0,0 +1,89 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/config"
+	appmetrics "github.com/volume-autoscaler/volume-autoscaler/internal/metrics"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/ratelimit"
+)
+
+// admitResize asks the resize limiter for a slot to patch pvc and returns a
+// *ratelimit.QueuedError when the expansion has to wait. PVCs are queued by
+// how full they are. A PVC whose limit cannot be resolved is not held back;
+// the StorageClass was already read by the safety checks.
+func (r *VolumeAutoscalerReconciler) admitResize(
+	ctx context.Context,
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	pvc *corev1.PersistentVolumeClaim,
+	status *autoscalingv1beta1.PVCStatus,
+	capBytes float64,
+	cfg *config.Config,
+	ttl time.Duration,
+) error {
+	if r.ResizeLimiter == nil || len(cfg.ResizeLimits) == 0 ||
+		pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return nil
+	}
+	var sc storagev1.StorageClass
+	if err := r.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, &sc); err != nil {
+		logf.FromContext(ctx).Error(err, "resolving resize limit failed, expanding anyway", "pvc", pvc.Name)
+		appmetrics.PollErrorsTotal.WithLabelValues(va.Namespace, va.Name, "resize_limit").Inc()
+		return nil
+	}
+	limit, ok := cfg.ResizeLimit(sc.Name, sc.Provisioner)
+	if !ok {
+		return nil
+	}
+	fullness := float64(status.UsageBytes) / capBytes
+	res := r.ResizeLimiter.Admit(r.now().Time, limit, client.ObjectKeyFromObject(pvc), fullness, ttl)
+	if res.Admitted {
+		return nil
+	}
+	return &ratelimit.QueuedError{Group: limit.Group(), Position: res.Position, RetryAfter: res.RetryAfter}
+}
+
+// releaseResize frees the resize slot held by pvc once its capacity has
+// caught up with the request.
+func (r *VolumeAutoscalerReconciler) releaseResize(pvc *corev1.PersistentVolumeClaim) {
+	if r.ResizeLimiter == nil {
+		return
+	}
+	for _, cond := range pvc.Status.Conditions {
+		if (cond.Type == corev1.PersistentVolumeClaimResizing ||
+			cond.Type == corev1.PersistentVolumeClaimFileSystemResizePending) &&
+			cond.Status == corev1.ConditionTrue {
+			return
+		}
+	}
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	if capacity.Cmp(requested) < 0 {
+		return
+	}
+	r.ResizeLimiter.Done(client.ObjectKeyFromObject(pvc))
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+)
+
+// Criteria reported in PVCStatus.TriggeredBy, named after the spec field or
+// annotation that fired.
+const (
+	TriggerThresholdPercent = "thresholdPercent"
+	TriggerMinFreeBytes     = "minFreeBytes"
+	TriggerEmergency        = "emergencyThresholdPercent"
+	TriggerExpandNow        = "expandNow"
+)
+
+// thresholdTriggers returns the regular threshold criteria that fired, or
+// nil if they do not call for an expansion under spec.trigger.mode.
+// freeKnown is false when freeBytes could not be measured; minFreeBytes then
+// neither fires nor, in "all" mode, holds the percentage back.
+func thresholdTriggers(
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	threshold, usagePercent int32,
+	freeBytes int64,
+	freeKnown bool,
+) []string {
+	var fired []string
+	if usagePercent >= threshold {
+		fired = append(fired, TriggerThresholdPercent)
+	}
+	if va.Spec.Trigger.MinFreeBytes == nil || !freeKnown {
+		return fired
+	}
+	if freeBytes < va.Spec.Trigger.MinFreeBytes.Value() {
+		fired = append(fired, TriggerMinFreeBytes)
+	}
+	if va.Spec.Trigger.Mode == autoscalingv1beta1.ThresholdModeAll && len(fired) < 2 {
+		return nil
+	}
+	return fired
+}
@@ -18,35 +18,94 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
-	autoscalingv1alpha1 "github.com/volume-autoscaler/volume-autoscaler/api/v1alpha1"
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/capacity"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/config"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/decision"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/health"
 	appmetrics "github.com/volume-autoscaler/volume-autoscaler/internal/metrics"
 	promclient "github.com/volume-autoscaler/volume-autoscaler/internal/prometheus"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/ratelimit"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/tracing"
 )
 
 const (
 	conditionReady     = "Ready"
 	defaultPollSecs    = 60
 	defaultCooldownSec = 300
-	requeueOnError     = 30 * time.Second
+
+	// finalizerName guards VolumeAutoscaler deletion until PVC annotations
+	// and per-PVC metric series have been cleaned up.
+	finalizerName = "autoscaling.volume-autoscaler.io/finalizer"
+
+	// annotationManagedBy is the ownership claim set on a PVC when it is expanded.
+	annotationManagedBy = "volume-autoscaler.io/managed-by"
+
+	// ShardLabel assigns a VolumeAutoscaler to the manager replica started with
+	// the matching --shard flag.
+	ShardLabel = "volume-autoscaler.io/shard"
+
+	// AnnotationExpandNow on a PVC requests a one-shot expansion on the next
+	// poll, regardless of threshold and cooldown. The controller removes it
+	// once the request has been handled.
+	AnnotationExpandNow = "volume-autoscaler.io/expand-now"
+
+	// AnnotationExclude set to "true" on a PVC opts it out of every
+	// VolumeAutoscaler that targets it.
+	AnnotationExclude = "volume-autoscaler.io/exclude"
+
+	// AnnotationMaxSize on a PVC overrides spec.limits.maxSize for that PVC.
+	AnnotationMaxSize = "volume-autoscaler.io/max-size"
+
+	// AnnotationLastScaleTime and AnnotationLastScaleSize record the last
+	// expansion on the PVC itself, written in the same patch as the new size.
+	// Cooldown is derived from them, so the record survives a failed status
+	// update, a crash or a leader failover.
+	AnnotationLastScaleTime = "volume-autoscaler.io/last-scale-time"
+	AnnotationLastScaleSize = "volume-autoscaler.io/last-scale-size"
 )
 
+// managedAnnotations lists the PVC annotations owned by the controller.
+// They are removed from claimed PVCs when the VolumeAutoscaler is deleted.
+var managedAnnotations = []string{
+	annotationManagedBy,
+	AnnotationExpandNow,
+	AnnotationAnomalyAck,
+	AnnotationLastScaleTime,
+	AnnotationLastScaleSize,
+}
+
+var tracer = otel.Tracer("github.com/volume-autoscaler/volume-autoscaler/internal/controller")
+
 // promClientCache stores Prometheus clients keyed by URL to avoid re-creating them.
 var (
 	promClients   = make(map[string]*promclient.Client)
@@ -69,6 +128,44 @@ type VolumeAutoscalerReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder events.EventRecorder
+
+	// MaxConcurrentReconciles is the number of VolumeAutoscalers reconciled in
+	// parallel. Defaults to 1.
+	MaxConcurrentReconciles int
+
+	// ReconcileTimeout bounds a single reconcile, including all Prometheus
+	// queries, so one slow backend cannot stall a worker indefinitely.
+	// Zero means no timeout.
+	ReconcileTimeout time.Duration
+
+	// Shard restricts this reconciler to VolumeAutoscalers whose ShardLabel
+	// matches. Empty means all VolumeAutoscalers are handled.
+	Shard string
+
+	// Config supplies controller-wide defaults and deny-lists. Nil uses
+	// config.Default().
+	Config *config.Store
+
+	// Clock drives cooldown and status timestamps. Nil uses the real clock;
+	// the simulation harness injects a fake one.
+	Clock clock.PassiveClock
+
+	// CapacityCheckers replaces the backend capacity checks selected by the
+	// controller config's backendCapacity. Nil uses the config.
+	CapacityCheckers []capacity.Checker
+
+	// HealthCheckers replaces the volume health checks, the kubelet metric
+	// and those selected by the controller config's volumeHealth. Nil uses
+	// both.
+	HealthCheckers []health.Checker
+
+	// Decisions receives one record per PVC per poll explaining what was
+	// done and why. Nil disables the decision log.
+	Decisions *decision.Log
+
+	// ResizeLimiter enforces the controller config's resizeLimits across all
+	// VolumeAutoscalers. Nil disables them.
+	ResizeLimiter *ratelimit.Limiter
 }
 
 // +kubebuilder:rbac:groups=autoscaling.volume-autoscaler.io,resources=volumeautoscalers,verbs=get;list;watch;create;update;patch;delete
@@ -76,7 +173,14 @@ type VolumeAutoscalerReconciler struct {
 // +kubebuilder:rbac:groups=autoscaling.volume-autoscaler.io,resources=volumeautoscalers/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups="",resources=persistentvolumes,verbs=get;list
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=csistoragecapacities,verbs=get;list
+// +kubebuilder:rbac:groups=longhorn.io,resources=nodes;replicas;settings;volumes,verbs=get;list
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get,resourceNames=volumeautoscalers.autoscaling.volume-autoscaler.io
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,verbs=update,resourceNames=volumeautoscalers.autoscaling.volume-autoscaler.io
+// +kubebuilder:rbac:groups="",resources=events,verbs=list
 // +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
 
@@ -87,25 +191,76 @@ func (r *VolumeAutoscalerReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		appmetrics.ReconcileDurationSeconds.Observe(time.Since(start).Seconds())
 	}()
 
+	ctx, span := tracer.Start(ctx, "Reconcile", trace.WithAttributes(
+		attribute.String("namespace", req.Namespace),
+		attribute.String("volumeautoscaler", req.Name),
+	))
+	defer span.End()
+
+	if r.ReconcileTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.ReconcileTimeout)
+		defer cancel()
+	}
+
 	// 1. Fetch the VolumeAutoscaler CR
-	var va autoscalingv1alpha1.VolumeAutoscaler
+	var va autoscalingv1beta1.VolumeAutoscaler
 	if err := r.Get(ctx, req.NamespacedName, &va); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// A pending requeue may outlive a shard label change
+	if !r.inShard(&va) {
+		return ctrl.Result{}, nil
+	}
+
+	// Read the defaults once so a reload mid-reconcile cannot mix values
+	cfg := r.defaults()
+
+	// Handle deletion: release claimed PVCs before letting the CR go
+	if !va.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, &va)
+	}
+	if !controllerutil.ContainsFinalizer(&va, finalizerName) {
+		controllerutil.AddFinalizer(&va, finalizerName)
+		if err := r.Update(ctx, &va); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	pollInterval := time.Duration(defaultPollSecs) * time.Second
-	if va.Spec.PollInterval != nil {
-		pollInterval = va.Spec.PollInterval.Duration
+	if va.Spec.Source.PollInterval != nil {
+		pollInterval = va.Spec.Source.PollInterval.Duration
 	}
-	cooldown := time.Duration(defaultCooldownSec) * time.Second
-	if va.Spec.CooldownPeriod != nil {
-		cooldown = va.Spec.CooldownPeriod.Duration
+	cooldown := CooldownFor(&va)
+
+	if Suspended(&va) {
+		log.V(1).Info("VolumeAutoscaler is suspended, skipping")
+		r.setCondition(&va, metav1.ConditionFalse, "Suspended", "autoscaling is suspended")
+		_ = r.Status().Update(ctx, &va)
+		return ctrl.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	if cfg.NamespaceDenied(va.Namespace) {
+		log.Info("namespace is denied by controller config, skipping")
+		r.setCondition(&va, metav1.ConditionFalse, "NamespaceDenied",
+			fmt.Sprintf("namespace %s is on the controller deny-list", va.Namespace))
+		_ = r.Status().Update(ctx, &va)
+		return ctrl.Result{RequeueAfter: pollInterval}, nil
 	}
 
 	// 2. Resolve target PVCs
-	pvcs, err := r.resolvePVCs(ctx, &va)
+	resolveCtx, resolveSpan := tracer.Start(ctx, "ResolvePVCs")
+	pvcs, err := r.resolvePVCs(resolveCtx, &va)
+	resolveSpan.SetAttributes(attribute.Int("pvcs", len(pvcs)))
+	tracing.End(resolveSpan, err)
 	if err != nil {
 		log.Error(err, "failed to resolve PVCs")
+		// The named PVC or the owner was deleted. Other errors may be
+		// transient, so the status, and the cooldowns in it, is kept.
+		if apierrors.IsNotFound(err) {
+			r.dropStalePVCs(&va, nil, cfg)
+		}
 		r.setCondition(&va, metav1.ConditionFalse, "NoPVCsFound", err.Error())
 		_ = r.Status().Update(ctx, &va)
 		appmetrics.PollErrorsTotal.WithLabelValues(va.Namespace, va.Name, "resolve_pvcs").Inc()
@@ -113,33 +268,40 @@ func (r *VolumeAutoscalerReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	}
 	if len(pvcs) == 0 {
 		log.Info("no PVCs found for target, will retry", "target", va.Spec.Target)
+		r.dropStalePVCs(&va, nil, cfg)
 		r.setCondition(&va, metav1.ConditionFalse, "NoPVCsFound", "no matching PVCs found")
 		_ = r.Status().Update(ctx, &va)
 		return ctrl.Result{RequeueAfter: pollInterval}, nil
 	}
 
 	// 3. Query Prometheus for volume stats
-	promURL := va.Spec.PrometheusURL
+	promURL := va.Spec.Source.Prometheus.URL
 	if promURL == "" {
-		promURL = "http://prometheus.monitoring.svc.cluster.local:9090"
+		promURL = cfg.PrometheusURL
 	}
 	prom := getPromClient(promURL)
 
-	now := metav1.Now()
+	now := r.now()
 	va.Status.LastPollTime = &now
 	va.Status.ObservedGeneration = va.Generation
 
 	// Build a map of existing PVC statuses for cooldown tracking
-	existingPVCStatus := make(map[string]*autoscalingv1alpha1.PVCStatus)
+	existingPVCStatus := make(map[string]*autoscalingv1beta1.PVCStatus)
 	for i := range va.Status.PVCs {
 		existingPVCStatus[va.Status.PVCs[i].Name] = &va.Status.PVCs[i]
 	}
 
-	var pvcStatuses []autoscalingv1alpha1.PVCStatus
+	var pvcStatuses []autoscalingv1beta1.PVCStatus
+	var reverts []string
+	short := map[string]string{}
+	unhealthy := map[string]string{}
 	allHealthy := true
+	requeueAfter := pollInterval
 
 	for _, pvc := range pvcs {
 		pvcLog := log.WithValues("pvc", pvc.Name, "namespace", pvc.Namespace)
+		rec := newDecision(&va, &pvc, now.Time)
+		r.releaseResize(&pvc)
 
 		// Query used bytes
 		usedQuery := fmt.Sprintf(
@@ -151,6 +313,7 @@ func (r *VolumeAutoscalerReconciler) Reconcile(ctx context.Context, req ctrl.Req
 			pvcLog.Error(err, "failed to query used bytes")
 			appmetrics.PollErrorsTotal.WithLabelValues(va.Namespace, va.Name, "prometheus_query").Inc()
 			allHealthy = false
+			r.decide(rec, decision.ActionFailed, "querying used bytes: "+err.Error())
 			continue
 		}
 
@@ -164,11 +327,13 @@ func (r *VolumeAutoscalerReconciler) Reconcile(ctx context.Context, req ctrl.Req
 			pvcLog.Error(err, "failed to query capacity bytes")
 			appmetrics.PollErrorsTotal.WithLabelValues(va.Namespace, va.Name, "prometheus_query").Inc()
 			allHealthy = false
+			r.decide(rec, decision.ActionFailed, "querying capacity bytes: "+err.Error())
 			continue
 		}
 
 		if capBytes <= 0 {
 			pvcLog.Info("capacity is zero or negative, skipping")
+			r.decide(rec, decision.ActionFailed, "reported capacity is zero")
 			continue
 		}
 
@@ -177,50 +342,149 @@ func (r *VolumeAutoscalerReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 		// Build PVC status
 		currentSize := pvc.Status.Capacity[corev1.ResourceStorage]
-		pvcStatus := autoscalingv1alpha1.PVCStatus{
+		pvcStatus := autoscalingv1beta1.PVCStatus{
 			Name:         pvc.Name,
 			CurrentSize:  currentSize,
 			UsageBytes:   int64(usedBytes),
 			UsagePercent: usagePercent,
+			FreeBytes:    int64(max(capBytes-usedBytes, 0)),
 		}
-		// Carry forward last scale info
-		if existing, ok := existingPVCStatus[pvc.Name]; ok {
+		// Carry forward last scale info and anomaly state
+		existing := existingPVCStatus[pvc.Name]
+		if existing != nil {
 			pvcStatus.LastScaleTime = existing.LastScaleTime
 			pvcStatus.LastScaleSize = existing.LastScaleSize
+			pvcStatus.RecentExpansions = recentExpansions(existing.RecentExpansions, now.Time)
+			pvcStatus.Anomaly = existing.Anomaly
+			pvcStatus.AnomalyAcknowledgedTime = existing.AnomalyAcknowledgedTime
 		}
+		// The PVC's own record wins over status, which may not have been written
+		applyScaleRecord(&pvc, &pvcStatus)
 
-		// 4. Check if expansion is needed
-		threshold := va.Spec.ThresholdPercent
+		threshold := va.Spec.Trigger.ThresholdPercent
 		if threshold == 0 {
-			threshold = 80
+			threshold = cfg.ThresholdPercent
 		}
+		setPVCReportGauges(&va, &pvc, &pvcStatus, threshold, now.Time)
+		rec.Inputs = decisionInputs(&va, &pvcStatus, capBytes, threshold)
 
-		if usagePercent >= threshold {
-			pvcLog.Info("usage exceeds threshold", "usage", usagePercent, "threshold", threshold)
+		// Flag abnormal growth before deciding whether to feed it
+		suppressed := r.checkAnomalies(ctx, &va, &pvc, existing, &pvcStatus, capBytes, prom)
+
+		// Detect an owner shrinking the request back, so we do not fight it
+		if requested, reverted := ownerReverted(&pvc, &pvcStatus); reverted {
+			pvcLog.Info("PVC request reverted below last expansion",
+				"requested", requested.String(), "lastScaleSize", pvcStatus.LastScaleSize.String())
+			reverts = append(reverts, fmt.Sprintf("%s (requested %s, expanded to %s by %s)",
+				pvc.Name, requested.String(), pvcStatus.LastScaleSize.String(), ownerName(&pvc)))
+			if pvc.Annotations[AnnotationExpandNow] == "" {
+				r.decide(rec, decision.ActionSkipped, fmt.Sprintf(
+					"owner reverted the request to %s, below the last expansion", requested.String()))
+				pvcStatuses = append(pvcStatuses, pvcStatus)
+				continue
+			}
+		}
+
+		// 4. Check if expansion is needed
+		triggers := thresholdTriggers(&va, threshold, usagePercent, pvcStatus.FreeBytes, true)
+		// Emergency mode expands past the cooldown when usage crosses the higher threshold
+		emergency := va.Spec.Trigger.EmergencyThresholdPercent > 0 && usagePercent >= va.Spec.Trigger.EmergencyThresholdPercent
+		if emergency {
+			triggers = append(triggers, TriggerEmergency)
+		}
+		// A forced expansion was requested via annotation (kubectl volumeautoscaler expand-now)
+		forced := pvc.Annotations[AnnotationExpandNow] != ""
+		if forced {
+			triggers = append(triggers, TriggerExpandNow)
+		}
+		pvcStatus.TriggeredBy = triggers
+		rec.TriggeredBy = triggers
+
+		if len(triggers) > 0 {
+			pvcCooldown := cooldown
+			switch {
+			case emergency:
+				pvcLog.Info("usage exceeds emergency threshold, ignoring cooldown",
+					"usage", usagePercent, "emergencyThreshold", va.Spec.Trigger.EmergencyThresholdPercent)
+				pvcCooldown = 0
+			case forced:
+				pvcLog.Info("forced expansion requested, ignoring threshold and cooldown", "usage", usagePercent)
+				pvcCooldown = 0
+			default:
+				pvcLog.Info("usage exceeds threshold", "usage", usagePercent, "threshold", threshold,
+					"freeBytes", pvcStatus.FreeBytes, "triggeredBy", triggers)
+			}
+
+			if suppressed && !forced {
+				pvcLog.Info("expansion suppressed by unacknowledged anomaly", "kind", pvcStatus.Anomaly.Kind)
+				rec.AddCheck(CheckAnomaly, fmt.Errorf("unacknowledged %s anomaly", pvcStatus.Anomaly.Kind))
+				r.decide(rec, decision.ActionSkipped, "expansion suppressed by unacknowledged anomaly")
+				pvcStatuses = append(pvcStatuses, pvcStatus)
+				continue
+			}
+			rec.AddCheck(CheckAnomaly, nil)
+
+			// Apply per-PVC overrides from annotations
+			pvcVA, err := withPVCOverrides(&va, &pvc)
+			rec.AddCheck(CheckOverride, err)
+			if err != nil {
+				pvcLog.Info("invalid PVC override, skipping expansion", "reason", err.Error())
+				r.Recorder.Eventf(&va, nil, corev1.EventTypeWarning, "InvalidOverride", "CheckExpansion",
+					"PVC %s/%s: %s", pvc.Namespace, pvc.Name, err.Error())
+				if forced {
+					r.rejectExpandNow(ctx, &va, &pvc, err.Error())
+				}
+				r.decide(rec, decision.ActionSkipped, "invalid PVC override: "+err.Error())
+				pvcStatuses = append(pvcStatuses, pvcStatus)
+				continue
+			}
+			rec.Inputs.MaxSize = pvcVA.Spec.Limits.MaxSize.String()
+
+			// Cooldown, zero for emergency and forced expansions
+			err = checkCooldown(&pvcStatus, pvcCooldown, r.now().Time)
+			rec.AddCheck(CheckCooldown, err)
+			if err != nil {
+				pvcLog.Info("cooldown not elapsed, skipping expansion", "reason", err.Error())
+				r.decide(rec, decision.ActionSkipped, err.Error())
+				pvcStatuses = append(pvcStatuses, pvcStatus)
+				continue
+			}
 
 			// Safety checks
-			if err := r.safetyChecks(ctx, &va, &pvc, &pvcStatus, cooldown); err != nil {
+			err = r.safetyChecks(ctx, pvcVA, &pvc)
+			rec.AddCheck(CheckSafety, err)
+			if err != nil {
 				pvcLog.Info("safety check failed, skipping expansion", "reason", err.Error())
+				if forced {
+					r.rejectExpandNow(ctx, &va, &pvc, err.Error())
+				}
+				r.decide(rec, decision.ActionSkipped, err.Error())
 				pvcStatuses = append(pvcStatuses, pvcStatus)
 				continue
 			}
 
-			// Check volume health
-			healthQuery := fmt.Sprintf(
-				`kubelet_volume_stats_health_abnormal{namespace="%s",persistentvolumeclaim="%s"}`,
-				pvc.Namespace, pvc.Name,
-			)
-			healthAbnormal, err := prom.Query(ctx, healthQuery)
-			if err == nil && healthAbnormal > 0 {
-				pvcLog.Info("volume is unhealthy, skipping expansion")
-				r.Recorder.Eventf(&va, nil, corev1.EventTypeWarning, "VolumeUnhealthy", "CheckHealth",
-					"PVC %s/%s is unhealthy, skipping expansion", pvc.Namespace, pvc.Name)
+			// Check volume health: growing a degraded volume makes it worse
+			unhealthyReport, healthErr := r.checkVolumeHealth(ctx, &va, &pvc, r.healthCheckers(cfg, prom))
+			if unhealthyReport != nil {
+				pvcLog.Info("volume is unhealthy, skipping expansion", "reason", unhealthyReport.Error())
+				pvcStatus.Unhealthy = &autoscalingv1beta1.PVCHealth{
+					Source:  unhealthyReport.Source,
+					Reason:  unhealthyReport.Reason,
+					Message: unhealthyReport.Message,
+				}
+				unhealthy[pvc.Name] = unhealthyReport.Error()
+				if forced {
+					r.rejectExpandNow(ctx, &va, &pvc, unhealthyReport.Error())
+				}
+				rec.AddCheck(CheckHealth, unhealthyReport)
+				r.decide(rec, decision.ActionSkipped, unhealthyReport.Error())
 				pvcStatuses = append(pvcStatuses, pvcStatus)
 				continue
 			}
+			rec.AddPassedCheck(CheckHealth, healthErr)
 
 			// Check inode threshold if configured
-			if va.Spec.InodeThresholdPercent > 0 {
+			if va.Spec.Trigger.InodeThresholdPercent > 0 {
 				inodesUsedQuery := fmt.Sprintf(
 					`kubelet_volume_stats_inodes_used{namespace="%s",persistentvolumeclaim="%s"}`,
 					pvc.Namespace, pvc.Name,
@@ -233,44 +497,126 @@ func (r *VolumeAutoscalerReconciler) Reconcile(ctx context.Context, req ctrl.Req
 				inodesTotal, err2 := prom.Query(ctx, inodesTotalQuery)
 				if err1 == nil && err2 == nil && inodesTotal > 0 {
 					inodePercent := int32(math.Round(inodesUsed / inodesTotal * 100))
-					if inodePercent >= va.Spec.InodeThresholdPercent {
-						pvcLog.Info("inode usage exceeds threshold", "inodeUsage", inodePercent, "threshold", va.Spec.InodeThresholdPercent)
+					rec.Inputs.InodeUsagePercent = &inodePercent
+					if inodePercent >= va.Spec.Trigger.InodeThresholdPercent {
+						pvcLog.Info("inode usage exceeds threshold", "inodeUsage", inodePercent, "threshold", va.Spec.Trigger.InodeThresholdPercent)
 					}
 				}
 			}
 
 			// 5. Calculate new size
-			newSize := r.calculateNewSize(&va, &currentSize)
-			pvcLog.Info("expanding PVC", "from", currentSize.String(), "to", newSize.String())
+			newSize := r.calculateNewSize(pvcVA, &currentSize)
+			if emergency {
+				newSize = r.calculateEmergencySize(pvcVA, &currentSize)
+			}
+
+			rec.NewSize = newSize.String()
+
+			// 6. Check the storage backend has room for the growth
+			err = r.checkBackendCapacity(ctx, &va, &pvc, newSize, cfg)
+			rec.AddCheck(CheckBackendCapacity, err)
+			if err != nil {
+				pvcLog.Info("insufficient backend capacity, skipping expansion", "reason", err.Error())
+				short[pvc.Name] = err.Error()
+				if forced {
+					r.rejectExpandNow(ctx, &va, &pvc, err.Error())
+				}
+				r.decide(rec, decision.ActionSkipped, err.Error())
+				pvcStatuses = append(pvcStatuses, pvcStatus)
+				continue
+			}
 
-			// 6. Patch PVC
+			// 7. Wait for a slot under the StorageClass or driver resize limit,
+			// asking again before the queue forgets this PVC
+			err = r.admitResize(ctx, &va, &pvc, &pvcStatus, capBytes, cfg, 2*pollInterval)
+			rec.AddCheck(CheckResizeLimit, err)
+			var queued *ratelimit.QueuedError
+			if errors.As(err, &queued) {
+				pvcLog.Info("expansion queued by resize limit", "group", queued.Group, "position", queued.Position)
+				if queued.RetryAfter > 0 {
+					requeueAfter = min(requeueAfter, max(queued.RetryAfter, time.Second))
+				}
+				r.decide(rec, decision.ActionQueued, err.Error())
+				pvcStatuses = append(pvcStatuses, pvcStatus)
+				continue
+			}
+			pvcLog.Info("expanding PVC", "from", currentSize.String(), "to", newSize.String(), "emergency", emergency)
+
+			// 8. Patch PVC, claiming it for this VolumeAutoscaler and recording
+			// the expansion in the same write
+			scaleTime := r.now()
 			patch := client.MergeFrom(pvc.DeepCopy())
 			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = newSize
-			if err := r.Patch(ctx, &pvc, patch); err != nil {
+			if pvc.Annotations == nil {
+				pvc.Annotations = make(map[string]string)
+			}
+			pvc.Annotations[annotationManagedBy] = va.Name
+			pvc.Annotations[AnnotationLastScaleTime] = scaleTime.UTC().Format(time.RFC3339)
+			pvc.Annotations[AnnotationLastScaleSize] = newSize.String()
+			delete(pvc.Annotations, AnnotationExpandNow)
+			patchCtx, patchSpan := tracer.Start(ctx, "PatchPVC", trace.WithAttributes(
+				attribute.String("pvc", pvc.Name), attribute.String("size", newSize.String())))
+			err = r.Patch(patchCtx, &pvc, patch)
+			tracing.End(patchSpan, err)
+			if err != nil {
 				pvcLog.Error(err, "failed to patch PVC")
 				r.Recorder.Eventf(&va, nil, corev1.EventTypeWarning, "ExpandFailed", "ExpandVolume",
 					"Failed to expand PVC %s/%s: %v", pvc.Namespace, pvc.Name, err)
 				appmetrics.PollErrorsTotal.WithLabelValues(va.Namespace, va.Name, "patch_pvc").Inc()
+				if r.ResizeLimiter != nil {
+					r.ResizeLimiter.Done(client.ObjectKeyFromObject(&pvc))
+				}
+				r.decide(rec, decision.ActionFailed, "patching PVC: "+err.Error())
 				pvcStatuses = append(pvcStatuses, pvcStatus)
 				continue
 			}
 
-			// 7. Emit event and update status
-			r.Recorder.Eventf(&va, nil, corev1.EventTypeNormal, "Expanded", "ExpandVolume",
-				"Expanded PVC %s/%s from %s to %s (usage: %d%%)",
-				pvc.Namespace, pvc.Name, currentSize.String(), newSize.String(), usagePercent)
-			appmetrics.ScaleEventsTotal.WithLabelValues(pvc.Namespace, pvc.Name, va.Name).Inc()
+			// 9. Emit event and update status
+			switch {
+			case emergency:
+				r.Recorder.Eventf(&va, nil, corev1.EventTypeWarning, "EmergencyExpanded", "ExpandVolume",
+					"Emergency-expanded PVC %s/%s from %s to %s (usage: %d%%, emergency threshold: %d%%)",
+					pvc.Namespace, pvc.Name, currentSize.String(), newSize.String(), usagePercent,
+					va.Spec.Trigger.EmergencyThresholdPercent)
+				appmetrics.ScaleEventsTotal.WithLabelValues(pvc.Namespace, pvc.Name, va.Name, appmetrics.ScaleModeEmergency).Inc()
+			case forced:
+				r.Recorder.Eventf(&va, nil, corev1.EventTypeNormal, "ForcedExpanded", "ExpandVolume",
+					"Force-expanded PVC %s/%s from %s to %s on request (usage: %d%%)",
+					pvc.Namespace, pvc.Name, currentSize.String(), newSize.String(), usagePercent)
+				appmetrics.ScaleEventsTotal.WithLabelValues(pvc.Namespace, pvc.Name, va.Name, appmetrics.ScaleModeForced).Inc()
+			default:
+				r.Recorder.Eventf(&va, nil, corev1.EventTypeNormal, "Expanded", "ExpandVolume",
+					"Expanded PVC %s/%s from %s to %s (usage: %d%%, triggered by %s)",
+					pvc.Namespace, pvc.Name, currentSize.String(), newSize.String(), usagePercent,
+					strings.Join(triggers, ", "))
+				appmetrics.ScaleEventsTotal.WithLabelValues(pvc.Namespace, pvc.Name, va.Name, appmetrics.ScaleModeNormal).Inc()
+			}
 
-			scaleTime := metav1.Now()
 			pvcStatus.LastScaleTime = &scaleTime
 			pvcStatus.LastScaleSize = &newSize
+			pvcStatus.RecentExpansions = append(pvcStatus.RecentExpansions, autoscalingv1beta1.PVCExpansion{
+				Time: scaleTime,
+				From: currentSize,
+				To:   newSize,
+			})
 			va.Status.TotalScaleEvents++
+			r.decide(rec, decision.ActionExpanded, "")
+		} else {
+			if r.ResizeLimiter != nil {
+				r.ResizeLimiter.Forget(client.ObjectKeyFromObject(&pvc))
+			}
+			r.decide(rec, decision.ActionNone, "no expansion criterion fired")
 		}
 
 		pvcStatuses = append(pvcStatuses, pvcStatus)
 	}
 
+	dropStaleSeries(&va, pvcs)
 	va.Status.PVCs = pvcStatuses
+	updateCost(&va, pvcs, cfg)
+	r.setOwnerReverted(&va, reverts)
+	r.setInsufficientCapacity(&va, pvcs, short)
+	r.setVolumeUnhealthy(&va, pvcs, unhealthy)
 
 	if allHealthy {
 		r.setCondition(&va, metav1.ConditionTrue, "Polling", "successfully polling volume metrics")
@@ -278,16 +624,129 @@ func (r *VolumeAutoscalerReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		r.setCondition(&va, metav1.ConditionFalse, "PrometheusUnavailable", "some metrics queries failed")
 	}
 
-	if err := r.Status().Update(ctx, &va); err != nil {
+	statusCtx, statusSpan := tracer.Start(ctx, "UpdateStatus")
+	err = r.Status().Update(statusCtx, &va)
+	tracing.End(statusSpan, err)
+	if err != nil {
 		log.Error(err, "failed to update status")
-		return ctrl.Result{RequeueAfter: requeueOnError}, nil
+		return ctrl.Result{RequeueAfter: cfg.RequeueOnError.Duration}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// dropStalePVCs forgets every PVC of va but those in pvcs: their status
+// entries, their metric series and their share of the cost.
+func (r *VolumeAutoscalerReconciler) dropStalePVCs(va *autoscalingv1beta1.VolumeAutoscaler, pvcs []corev1.PersistentVolumeClaim, cfg *config.Config) {
+	dropStaleSeries(va, pvcs)
+	current := make(map[string]bool, len(pvcs))
+	for _, pvc := range pvcs {
+		current[pvc.Name] = true
+	}
+	va.Status.PVCs = slices.DeleteFunc(va.Status.PVCs, func(st autoscalingv1beta1.PVCStatus) bool {
+		return !current[st.Name]
+	})
+	updateCost(va, pvcs, cfg)
+}
+
+// dropStaleSeries deletes the per-PVC series of the PVCs in va's status that
+// are not in pvcs: no longer targeted, or deleted.
+func dropStaleSeries(va *autoscalingv1beta1.VolumeAutoscaler, pvcs []corev1.PersistentVolumeClaim) {
+	current := make(map[string]bool, len(pvcs))
+	for _, pvc := range pvcs {
+		current[pvc.Name] = true
+	}
+	for _, st := range va.Status.PVCs {
+		if current[st.Name] {
+			continue
+		}
+		appmetrics.PVCUsagePercent.DeleteLabelValues(va.Namespace, st.Name, va.Name)
+		appmetrics.PVCThresholdPercent.DeleteLabelValues(va.Namespace, st.Name, va.Name)
+		appmetrics.PVCSizeBytes.DeleteLabelValues(va.Namespace, st.Name, va.Name)
+		appmetrics.PVCMaxSizeBytes.DeleteLabelValues(va.Namespace, st.Name, va.Name)
+		appmetrics.PVCResizePendingSeconds.DeleteLabelValues(va.Namespace, st.Name, va.Name)
+		appmetrics.PVCAnomalous.DeleteLabelValues(va.Namespace, st.Name, va.Name)
+		appmetrics.BackendCapacityInsufficient.DeleteLabelValues(va.Namespace, st.Name, va.Name)
+		appmetrics.PVCUnhealthy.DeleteLabelValues(va.Namespace, st.Name, va.Name)
+	}
+}
+
+// finalize releases every PVC claimed by the VolumeAutoscaler, removes its
+// metric series and then drops the finalizer so deletion can proceed.
+func (r *VolumeAutoscalerReconciler) finalize(ctx context.Context, va *autoscalingv1beta1.VolumeAutoscaler) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(va, finalizerName) {
+		return ctrl.Result{}, nil
 	}
 
-	return ctrl.Result{RequeueAfter: pollInterval}, nil
+	// Claims are found by annotation rather than by target, so PVCs that no
+	// longer match the selector are released too.
+	var pvcList corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &pvcList, client.InNamespace(va.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("listing PVCs for cleanup: %w", err)
+	}
+	released := 0
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if pvc.Annotations[annotationManagedBy] != va.Name {
+			continue
+		}
+		patch := client.MergeFrom(pvc.DeepCopy())
+		for _, key := range managedAnnotations {
+			delete(pvc.Annotations, key)
+		}
+		if err := r.Patch(ctx, pvc, patch); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("releasing PVC %s: %w", pvc.Name, err)
+		}
+		released++
+	}
+
+	series := prometheus.Labels{
+		"namespace":        va.Namespace,
+		"volumeautoscaler": va.Name,
+	}
+	appmetrics.PVCUsagePercent.DeletePartialMatch(series)
+	appmetrics.PVCThresholdPercent.DeletePartialMatch(series)
+	appmetrics.PVCSizeBytes.DeletePartialMatch(series)
+	appmetrics.PVCMaxSizeBytes.DeletePartialMatch(series)
+	appmetrics.PVCResizePendingSeconds.DeletePartialMatch(series)
+	appmetrics.PVCAnomalous.DeletePartialMatch(series)
+	appmetrics.BackendCapacityInsufficient.DeletePartialMatch(series)
+	appmetrics.PVCUnhealthy.DeletePartialMatch(series)
+	appmetrics.ProvisionedCostMonthly.DeletePartialMatch(series)
+	appmetrics.AutoscaledCostMonthly30d.DeletePartialMatch(series)
+
+	r.Recorder.Eventf(va, nil, corev1.EventTypeNormal, "Finalized", "Cleanup",
+		"Released %d PVC(s) after %d expansion(s) across %d tracked PVC(s)",
+		released, va.Status.TotalScaleEvents, len(va.Status.PVCs))
+	log.Info("finalized VolumeAutoscaler", "releasedPVCs", released, "totalScaleEvents", va.Status.TotalScaleEvents)
+
+	controllerutil.RemoveFinalizer(va, finalizerName)
+	if err := r.Update(ctx, va); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// rejectExpandNow clears a forced expansion request that could not be honoured,
+// so it does not fire later when the blocking condition clears.
+func (r *VolumeAutoscalerReconciler) rejectExpandNow(
+	ctx context.Context,
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	pvc *corev1.PersistentVolumeClaim,
+	reason string,
+) {
+	r.Recorder.Eventf(va, nil, corev1.EventTypeWarning, "ForcedExpansionRejected", "ExpandVolume",
+		"Forced expansion of PVC %s/%s rejected: %s", pvc.Namespace, pvc.Name, reason)
+	patch := client.MergeFrom(pvc.DeepCopy())
+	delete(pvc.Annotations, AnnotationExpandNow)
+	if err := r.Patch(ctx, pvc, patch); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to clear expand-now annotation", "pvc", pvc.Name)
+	}
 }
 
 // resolvePVCs returns the PVCs targeted by the VolumeAutoscaler CR.
-func (r *VolumeAutoscalerReconciler) resolvePVCs(ctx context.Context, va *autoscalingv1alpha1.VolumeAutoscaler) ([]corev1.PersistentVolumeClaim, error) {
+func (r *VolumeAutoscalerReconciler) resolvePVCs(ctx context.Context, va *autoscalingv1beta1.VolumeAutoscaler) ([]corev1.PersistentVolumeClaim, error) {
 	if va.Spec.Target.PVCName != "" {
 		var pvc corev1.PersistentVolumeClaim
 		if err := r.Get(ctx, types.NamespacedName{
@@ -296,6 +755,9 @@ func (r *VolumeAutoscalerReconciler) resolvePVCs(ctx context.Context, va *autosc
 		}, &pvc); err != nil {
 			return nil, err
 		}
+		if excluded(&pvc) {
+			return nil, nil
+		}
 		return []corev1.PersistentVolumeClaim{pvc}, nil
 	}
 
@@ -311,20 +773,50 @@ func (r *VolumeAutoscalerReconciler) resolvePVCs(ctx context.Context, va *autosc
 		); err != nil {
 			return nil, err
 		}
-		return pvcList.Items, nil
+		pvcs := make([]corev1.PersistentVolumeClaim, 0, len(pvcList.Items))
+		for _, pvc := range pvcList.Items {
+			if !excluded(&pvc) {
+				pvcs = append(pvcs, pvc)
+			}
+		}
+		return pvcs, nil
+	}
+
+	if va.Spec.Target.OwnerRef != nil {
+		return r.resolveOwnedPVCs(ctx, va)
 	}
 
-	return nil, fmt.Errorf("target must specify either pvcName or selector")
+	return nil, fmt.Errorf("target must specify one of pvcName, selector or ownerRef")
+}
+
+// checkCooldown fails while less than cooldown has passed since the PVC's
+// last expansion.
+func checkCooldown(pvcStatus *autoscalingv1beta1.PVCStatus, cooldown time.Duration, now time.Time) error {
+	if pvcStatus.LastScaleTime == nil {
+		return nil
+	}
+	elapsed := now.Sub(pvcStatus.LastScaleTime.Time)
+	if elapsed < cooldown {
+		return fmt.Errorf("cooldown not elapsed (%s remaining)", (cooldown - elapsed).Round(time.Second))
+	}
+	return nil
 }
 
 // safetyChecks validates that a PVC can be safely expanded.
 func (r *VolumeAutoscalerReconciler) safetyChecks(
 	ctx context.Context,
-	va *autoscalingv1alpha1.VolumeAutoscaler,
+	va *autoscalingv1beta1.VolumeAutoscaler,
 	pvc *corev1.PersistentVolumeClaim,
-	pvcStatus *autoscalingv1alpha1.PVCStatus,
-	cooldown time.Duration,
-) error {
+) (err error) {
+	ctx, span := tracer.Start(ctx, "SafetyChecks", trace.WithAttributes(attribute.String("pvc", pvc.Name)))
+	defer func() {
+		// A blocked expansion is an outcome, not a failure
+		if err != nil {
+			span.SetAttributes(attribute.String("blocked", err.Error()))
+		}
+		span.End()
+	}()
+
 	// Check if PVC is already being resized
 	for _, cond := range pvc.Status.Conditions {
 		if cond.Type == corev1.PersistentVolumeClaimResizing ||
@@ -335,26 +827,27 @@ func (r *VolumeAutoscalerReconciler) safetyChecks(
 		}
 	}
 
-	// Check cooldown
-	if pvcStatus.LastScaleTime != nil {
-		elapsed := time.Since(pvcStatus.LastScaleTime.Time)
-		if elapsed < cooldown {
-			return fmt.Errorf("cooldown not elapsed (%s remaining)", (cooldown - elapsed).Round(time.Second))
-		}
-	}
-
 	// Check if current size already at maxSize
 	currentSize := pvc.Status.Capacity[corev1.ResourceStorage]
-	if currentSize.Cmp(va.Spec.MaxSize) >= 0 {
+	if currentSize.Cmp(va.Spec.Limits.MaxSize) >= 0 {
 		r.Recorder.Eventf(va, nil, corev1.EventTypeWarning, "MaxSizeReached", "CheckExpansion",
-			"PVC %s/%s has reached maxSize %s", pvc.Namespace, pvc.Name, va.Spec.MaxSize.String())
-		return fmt.Errorf("PVC already at maxSize %s", va.Spec.MaxSize.String())
+			"PVC %s/%s has reached maxSize %s", pvc.Namespace, pvc.Name, va.Spec.Limits.MaxSize.String())
+		return fmt.Errorf("PVC already at maxSize %s", va.Spec.Limits.MaxSize.String())
 	}
 
 	// Check StorageClass allows expansion
 	if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+		if r.defaults().StorageClassDenied(*pvc.Spec.StorageClassName) {
+			r.Recorder.Eventf(va, nil, corev1.EventTypeWarning, "StorageClassDenied", "CheckExpansion",
+				"StorageClass %s is on the controller deny-list", *pvc.Spec.StorageClassName)
+			return fmt.Errorf("StorageClass %s is denied by controller config", *pvc.Spec.StorageClassName)
+		}
 		var sc storagev1.StorageClass
-		if err := r.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, &sc); err != nil {
+		scCtx, scSpan := tracer.Start(ctx, "GetStorageClass",
+			trace.WithAttributes(attribute.String("storageclass", *pvc.Spec.StorageClassName)))
+		err := r.Get(scCtx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, &sc)
+		tracing.End(scSpan, err)
+		if err != nil {
 			return fmt.Errorf("failed to get StorageClass: %w", err)
 		}
 		if sc.AllowVolumeExpansion == nil || !*sc.AllowVolumeExpansion {
@@ -369,63 +862,219 @@ func (r *VolumeAutoscalerReconciler) safetyChecks(
 
 // calculateNewSize computes the target size after expansion.
 func (r *VolumeAutoscalerReconciler) calculateNewSize(
-	va *autoscalingv1alpha1.VolumeAutoscaler,
+	va *autoscalingv1beta1.VolumeAutoscaler,
 	currentSize *resource.Quantity,
 ) resource.Quantity {
-	increasePercent := va.Spec.IncreasePercent
+	increasePercent := va.Spec.Growth.IncreasePercent
 	if increasePercent == 0 {
-		increasePercent = 20
+		increasePercent = r.defaults().IncreasePercent
 	}
+	if step := growthStep(va.Spec.Growth.Steps, currentSize); step != nil {
+		increasePercent = step.IncreasePercent
+	}
+	return r.growSize(va, currentSize, increasePercent)
+}
+
+// growthStep returns the step with the largest aboveSize not exceeding
+// currentSize, or nil if currentSize is below every step.
+func growthStep(steps []autoscalingv1beta1.GrowthStep, currentSize *resource.Quantity) *autoscalingv1beta1.GrowthStep {
+	var best *autoscalingv1beta1.GrowthStep
+	for i := range steps {
+		step := &steps[i]
+		if step.AboveSize.Cmp(*currentSize) > 0 {
+			continue
+		}
+		if best == nil || step.AboveSize.Cmp(best.AboveSize) > 0 {
+			best = step
+		}
+	}
+	return best
+}
 
+// calculateEmergencySize computes the target size for an emergency expansion,
+// using emergencyIncreasePercent instead of increasePercent.
+func (r *VolumeAutoscalerReconciler) calculateEmergencySize(
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	currentSize *resource.Quantity,
+) resource.Quantity {
+	increasePercent := va.Spec.Growth.EmergencyIncreasePercent
+	if increasePercent == 0 {
+		increasePercent = r.defaults().EmergencyIncreasePercent
+	}
+	return r.growSize(va, currentSize, increasePercent)
+}
+
+// growSize adds increasePercent of the current size, honouring the minimum
+// and maximum increase and the maxSize cap.
+func (r *VolumeAutoscalerReconciler) growSize(
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	currentSize *resource.Quantity,
+	increasePercent int32,
+) resource.Quantity {
 	// Calculate percentage-based increase
 	currentBytes := currentSize.Value()
 	increaseBytes := currentBytes * int64(increasePercent) / 100
 
-	// Apply minimum floor
-	if va.Spec.IncreaseMinimum != nil {
-		minBytes := va.Spec.IncreaseMinimum.Value()
-		if increaseBytes < minBytes {
-			increaseBytes = minBytes
-		}
-	} else {
-		// Default minimum: 1Gi
-		oneGi := func() int64 { q := resource.MustParse("1Gi"); return q.Value() }()
-		if increaseBytes < oneGi {
-			increaseBytes = oneGi
-		}
+	// Apply minimum floor, falling back to the controller default (1Gi)
+	minBytes := r.defaults().IncreaseMinimum.Value()
+	if va.Spec.Growth.MinIncrement != nil {
+		minBytes = va.Spec.Growth.MinIncrement.Value()
+	}
+	if increaseBytes < minBytes {
+		increaseBytes = minBytes
+	}
+	if maxIncrement := va.Spec.Growth.MaxIncrement; maxIncrement != nil && increaseBytes > maxIncrement.Value() {
+		increaseBytes = maxIncrement.Value()
 	}
 
 	newBytes := currentBytes + increaseBytes
 	newSize := *resource.NewQuantity(newBytes, resource.BinarySI)
 
 	// Cap at maxSize
-	if newSize.Cmp(va.Spec.MaxSize) > 0 {
-		newSize = va.Spec.MaxSize.DeepCopy()
+	if newSize.Cmp(va.Spec.Limits.MaxSize) > 0 {
+		newSize = va.Spec.Limits.MaxSize.DeepCopy()
 	}
 
 	return newSize
 }
 
-// setCondition updates or adds a condition on the VolumeAutoscaler status.
+// setCondition updates or adds the Ready condition on the VolumeAutoscaler status.
 func (r *VolumeAutoscalerReconciler) setCondition(
-	va *autoscalingv1alpha1.VolumeAutoscaler,
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	status metav1.ConditionStatus,
+	reason, message string,
+) {
+	r.setConditionType(va, conditionReady, status, reason, message)
+}
+
+// setConditionType updates or adds a condition of the given type.
+func (r *VolumeAutoscalerReconciler) setConditionType(
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	conditionType string,
 	status metav1.ConditionStatus,
 	reason, message string,
 ) {
 	meta.SetStatusCondition(&va.Status.Conditions, metav1.Condition{
-		Type:               conditionReady,
+		Type:               conditionType,
 		Status:             status,
 		ObservedGeneration: va.Generation,
-		LastTransitionTime: metav1.Now(),
+		LastTransitionTime: r.now(),
 		Reason:             reason,
 		Message:            message,
 	})
 }
 
+// setPVCReportGauges exports the PVC's size, effective threshold and maxSize,
+// and how long its last expansion has been waiting for the volume to grow, so
+// usage can be judged from metrics alone (see internal/aggregator).
+func setPVCReportGauges(
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	pvc *corev1.PersistentVolumeClaim,
+	pvcStatus *autoscalingv1beta1.PVCStatus,
+	threshold int32,
+	now time.Time,
+) {
+	appmetrics.PVCThresholdPercent.WithLabelValues(pvc.Namespace, pvc.Name, va.Name).Set(float64(threshold))
+
+	appmetrics.PVCSizeBytes.WithLabelValues(pvc.Namespace, pvc.Name, va.Name).Set(float64(pvcStatus.CurrentSize.Value()))
+
+	maxSize := va.Spec.Limits.MaxSize
+	if pvcVA, err := withPVCOverrides(va, pvc); err == nil {
+		maxSize = pvcVA.Spec.Limits.MaxSize
+	}
+	appmetrics.PVCMaxSizeBytes.WithLabelValues(pvc.Namespace, pvc.Name, va.Name).Set(float64(maxSize.Value()))
+
+	pending := 0.0
+	if pvcStatus.LastScaleTime != nil && pvcStatus.LastScaleSize != nil &&
+		pvcStatus.CurrentSize.Cmp(*pvcStatus.LastScaleSize) < 0 {
+		pending = now.Sub(pvcStatus.LastScaleTime.Time).Seconds()
+	}
+	appmetrics.PVCResizePendingSeconds.WithLabelValues(pvc.Namespace, pvc.Name, va.Name).Set(pending)
+}
+
+// Suspended reports whether autoscaling is paused for the VolumeAutoscaler.
+func Suspended(va *autoscalingv1beta1.VolumeAutoscaler) bool {
+	return va.Spec.Suspend
+}
+
+// excluded reports whether the PVC has opted out of autoscaling.
+func excluded(pvc *corev1.PersistentVolumeClaim) bool {
+	return pvc.Annotations[AnnotationExclude] == "true"
+}
+
+// withPVCOverrides returns the VolumeAutoscaler as it applies to one PVC,
+// with per-PVC annotation overrides folded into the spec. The original is
+// returned unchanged when the PVC has no overrides.
+func withPVCOverrides(
+	va *autoscalingv1beta1.VolumeAutoscaler,
+	pvc *corev1.PersistentVolumeClaim,
+) (*autoscalingv1beta1.VolumeAutoscaler, error) {
+	raw, ok := pvc.Annotations[AnnotationMaxSize]
+	if !ok {
+		return va, nil
+	}
+	maxSize, err := resource.ParseQuantity(raw)
+	if err != nil || maxSize.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid %s annotation %q", AnnotationMaxSize, raw)
+	}
+	out := va.DeepCopy()
+	out.Spec.Limits.MaxSize = maxSize
+	return out, nil
+}
+
+// applyScaleRecord overrides the last expansion in pvcStatus with the record
+// annotated on the PVC, when present and valid.
+func applyScaleRecord(pvc *corev1.PersistentVolumeClaim, pvcStatus *autoscalingv1beta1.PVCStatus) {
+	raw, ok := pvc.Annotations[AnnotationLastScaleTime]
+	if !ok {
+		return
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return
+	}
+	scaleTime := metav1.NewTime(t)
+	pvcStatus.LastScaleTime = &scaleTime
+	if size, err := resource.ParseQuantity(pvc.Annotations[AnnotationLastScaleSize]); err == nil {
+		pvcStatus.LastScaleSize = &size
+	}
+}
+
+// CooldownFor returns the cooldown period configured on the VolumeAutoscaler.
+func CooldownFor(va *autoscalingv1beta1.VolumeAutoscaler) time.Duration {
+	if va.Spec.Growth.CooldownPeriod != nil {
+		return va.Spec.Growth.CooldownPeriod.Duration
+	}
+	return time.Duration(defaultCooldownSec) * time.Second
+}
+
+// now returns the current time from the reconciler's clock.
+func (r *VolumeAutoscalerReconciler) now() metav1.Time {
+	if r.Clock == nil {
+		return metav1.Now()
+	}
+	return metav1.NewTime(r.Clock.Now())
+}
+
+// defaults returns the active controller-wide configuration.
+func (r *VolumeAutoscalerReconciler) defaults() *config.Config {
+	if r.Config == nil {
+		return config.Default()
+	}
+	return r.Config.Get()
+}
+
+// inShard reports whether the object belongs to this reconciler's shard.
+func (r *VolumeAutoscalerReconciler) inShard(obj client.Object) bool {
+	return r.Shard == "" || obj.GetLabels()[ShardLabel] == r.Shard
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *VolumeAutoscalerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&autoscalingv1alpha1.VolumeAutoscaler{}).
+		For(&autoscalingv1beta1.VolumeAutoscaler{}).
+		WithEventFilter(predicate.NewPredicateFuncs(r.inShard)).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Named("volumeautoscaler").
 		Complete(r)
 }
@@ -21,18 +21,29 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	autoscalingv1alpha1 "github.com/volume-autoscaler/volume-autoscaler/api/v1alpha1"
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/config"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/decision"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/health"
+	appmetrics "github.com/volume-autoscaler/volume-autoscaler/internal/metrics"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/ratelimit"
 )
 
 var _ = Describe("VolumeAutoscaler Controller", func() {
@@ -91,34 +102,38 @@ var _ = Describe("VolumeAutoscaler Controller", func() {
 
 	Context("When VolumeAutoscaler CR is created", func() {
 		It("should handle missing PVC gracefully", func() {
-			va := &autoscalingv1alpha1.VolumeAutoscaler{
+			va := &autoscalingv1beta1.VolumeAutoscaler{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      vaName,
 					Namespace: vaNamespace,
 				},
-				Spec: autoscalingv1alpha1.VolumeAutoscalerSpec{
-					Target: autoscalingv1alpha1.VolumeAutoscalerTarget{
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Target: autoscalingv1beta1.VolumeAutoscalerTarget{
 						PVCName: "nonexistent-pvc",
 					},
-					MaxSize:       resource.MustParse("100Gi"),
-					PrometheusURL: promServer.URL,
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
+					Source: autoscalingv1beta1.SourceSpec{Prometheus: autoscalingv1beta1.PrometheusSource{URL: promServer.URL}},
 				},
 			}
 
 			Expect(k8sClient.Create(ctx, va)).To(Succeed())
-			defer func() {
-				resource := &autoscalingv1alpha1.VolumeAutoscaler{}
-				err := k8sClient.Get(ctx, types.NamespacedName{Name: vaName, Namespace: vaNamespace}, resource)
-				if err == nil {
-					Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
-				}
-			}()
 
 			reconciler := &VolumeAutoscalerReconciler{
 				Client:   k8sClient,
 				Scheme:   k8sClient.Scheme(),
 				Recorder: events.NewFakeRecorder(10),
 			}
+			defer func() {
+				resource := &autoscalingv1beta1.VolumeAutoscaler{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: vaName, Namespace: vaNamespace}, resource)
+				if err == nil {
+					Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+					// Run the finalizer so the CR is actually removed
+					_, _ = reconciler.Reconcile(ctx, reconcile.Request{
+						NamespacedName: types.NamespacedName{Name: vaName, Namespace: vaNamespace},
+					})
+				}
+			}()
 
 			result, err := reconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: types.NamespacedName{Name: vaName, Namespace: vaNamespace},
@@ -127,10 +142,190 @@ var _ = Describe("VolumeAutoscaler Controller", func() {
 			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
 
 			// Status should show NoPVCsFound condition
-			updatedVA := &autoscalingv1alpha1.VolumeAutoscaler{}
+			updatedVA := &autoscalingv1beta1.VolumeAutoscaler{}
 			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: vaName, Namespace: vaNamespace}, updatedVA)).To(Succeed())
 			Expect(updatedVA.Status.Conditions).NotTo(BeEmpty())
 			Expect(updatedVA.Status.Conditions[0].Reason).To(Equal("NoPVCsFound"))
+			Expect(updatedVA.Finalizers).To(ContainElement(finalizerName))
+		})
+
+		It("should reject a target without exactly one of pvcName, selector and ownerRef", func() {
+			for _, target := range []autoscalingv1beta1.VolumeAutoscalerTarget{
+				{},
+				{PVCName: pvcName, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "loki"}}},
+				{Selector: &metav1.LabelSelector{}, OwnerRef: &autoscalingv1beta1.VolumeAutoscalerOwnerRef{Kind: "StatefulSet", Name: "loki"}},
+			} {
+				va := &autoscalingv1beta1.VolumeAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{Name: "invalid-target-va", Namespace: vaNamespace},
+					Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+						Target: target,
+						Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
+					},
+				}
+				err := k8sClient.Create(ctx, va)
+				Expect(errors.IsInvalid(err)).To(BeTrue(), "target %+v: %v", target, err)
+				Expect(err.Error()).To(ContainSubstring("exactly one of pvcName, selector and ownerRef must be set"))
+			}
+		})
+
+		It("should reject an emergency threshold not above the threshold", func() {
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "invalid-emergency-va", Namespace: vaNamespace},
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Target: autoscalingv1beta1.VolumeAutoscalerTarget{PVCName: pvcName},
+					Trigger: autoscalingv1beta1.TriggerSpec{
+						ThresholdPercent:          90,
+						EmergencyThresholdPercent: 85,
+					},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
+				},
+			}
+			err := k8sClient.Create(ctx, va)
+			Expect(errors.IsInvalid(err)).To(BeTrue(), "%v", err)
+			Expect(err.Error()).To(ContainSubstring("emergencyThresholdPercent must be greater than thresholdPercent"))
+		})
+
+		It("should reject the reserved forecast, budget and auth fields", func() {
+			for field, spec := range map[string]autoscalingv1beta1.VolumeAutoscalerSpec{
+				"forecast": {Trigger: autoscalingv1beta1.TriggerSpec{Forecast: &autoscalingv1beta1.ForecastSpec{}}},
+				"budget":   {Limits: autoscalingv1beta1.LimitsSpec{Budget: &autoscalingv1beta1.BudgetSpec{MaxMonthlyCost: "250"}}},
+				"auth": {Source: autoscalingv1beta1.SourceSpec{Prometheus: autoscalingv1beta1.PrometheusSource{
+					Auth: &autoscalingv1beta1.PrometheusAuth{},
+				}}},
+			} {
+				spec.Target = autoscalingv1beta1.VolumeAutoscalerTarget{PVCName: pvcName}
+				spec.Limits.MaxSize = resource.MustParse("100Gi")
+				va := &autoscalingv1beta1.VolumeAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{Name: "reserved-field-va", Namespace: vaNamespace},
+					Spec:       spec,
+				}
+				err := k8sClient.Create(ctx, va)
+				Expect(errors.IsInvalid(err)).To(BeTrue(), "%s: %v", field, err)
+				Expect(err.Error()).To(ContainSubstring(field + " is not implemented yet"))
+			}
+		})
+
+		It("should forget the status and metrics of a deleted target PVC", func() {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: vaNamespace},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "deleted-pvc-va", Namespace: vaNamespace},
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Target: autoscalingv1beta1.VolumeAutoscalerTarget{PVCName: pvcName},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
+					Source: autoscalingv1beta1.SourceSpec{Prometheus: autoscalingv1beta1.PrometheusSource{URL: promServer.URL}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, va)).To(Succeed())
+
+			reconciler := &VolumeAutoscalerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: events.NewFakeRecorder(10),
+			}
+			key := types.NamespacedName{Name: "deleted-pvc-va", Namespace: vaNamespace}
+			defer func() {
+				Expect(k8sClient.Delete(ctx, va)).To(Succeed())
+				_, _ = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			}()
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			updatedVA := &autoscalingv1beta1.VolumeAutoscaler{}
+			Expect(k8sClient.Get(ctx, key, updatedVA)).To(Succeed())
+			Expect(updatedVA.Status.PVCs).To(HaveLen(1))
+
+			// Drop the protection finalizer, nothing in envtest would remove it
+			Expect(k8sClient.Delete(ctx, pvc)).To(Succeed())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: vaNamespace}, pvc)).To(Succeed())
+			pvc.Finalizers = nil
+			Expect(k8sClient.Update(ctx, pvc)).To(Succeed())
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: vaNamespace}, pvc)
+				return errors.IsNotFound(err)
+			}).Should(BeTrue())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, updatedVA)).To(Succeed())
+			Expect(updatedVA.Status.PVCs).To(BeEmpty())
+			Expect(meta.FindStatusCondition(updatedVA.Status.Conditions, conditionReady).Reason).To(Equal("NoPVCsFound"))
+
+			// DeleteLabelValues reports whether the series was still exported
+			Expect(appmetrics.PVCUsagePercent.DeleteLabelValues(vaNamespace, pvcName, va.Name)).To(BeFalse())
+			Expect(appmetrics.PVCSizeBytes.DeleteLabelValues(vaNamespace, pvcName, va.Name)).To(BeFalse())
+			Expect(appmetrics.PVCMaxSizeBytes.DeleteLabelValues(vaNamespace, pvcName, va.Name)).To(BeFalse())
+		})
+
+		It("should release claimed PVCs and remove the finalizer on deletion", func() {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "claimed-pvc",
+					Namespace: vaNamespace,
+					Annotations: map[string]string{
+						annotationManagedBy: vaName,
+						"example.com/keep":  "true",
+					},
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse("10Gi"),
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+			defer func() {
+				_ = k8sClient.Delete(ctx, pvc)
+			}()
+
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       vaName,
+					Namespace:  vaNamespace,
+					Finalizers: []string{finalizerName},
+				},
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Target: autoscalingv1beta1.VolumeAutoscalerTarget{
+						PVCName: "claimed-pvc",
+					},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
+					Source: autoscalingv1beta1.SourceSpec{Prometheus: autoscalingv1beta1.PrometheusSource{URL: promServer.URL}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, va)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, va)).To(Succeed())
+
+			recorder := events.NewFakeRecorder(10)
+			reconciler := &VolumeAutoscalerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: vaName, Namespace: vaNamespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: vaName, Namespace: vaNamespace}, &autoscalingv1beta1.VolumeAutoscaler{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			updatedPVC := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "claimed-pvc", Namespace: vaNamespace}, updatedPVC)).To(Succeed())
+			Expect(updatedPVC.Annotations).NotTo(HaveKey(annotationManagedBy))
+			Expect(updatedPVC.Annotations).To(HaveKeyWithValue("example.com/keep", "true"))
+
+			Expect(recorder.Events).To(Receive(ContainSubstring("Finalized")))
 		})
 
 		It("should reconcile successfully when CR is deleted", func() {
@@ -151,10 +346,10 @@ var _ = Describe("VolumeAutoscaler Controller", func() {
 	Context("When calculating new size", func() {
 		It("should increase by percentage", func() {
 			reconciler := &VolumeAutoscalerReconciler{}
-			va := &autoscalingv1alpha1.VolumeAutoscaler{
-				Spec: autoscalingv1alpha1.VolumeAutoscalerSpec{
-					IncreasePercent: 20,
-					MaxSize:         resource.MustParse("100Gi"),
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Growth: autoscalingv1beta1.GrowthSpec{IncreasePercent: 20},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
 				},
 			}
 			currentSize := resource.MustParse("10Gi")
@@ -167,11 +362,13 @@ var _ = Describe("VolumeAutoscaler Controller", func() {
 		It("should apply minimum floor", func() {
 			reconciler := &VolumeAutoscalerReconciler{}
 			minIncrease := resource.MustParse("5Gi")
-			va := &autoscalingv1alpha1.VolumeAutoscaler{
-				Spec: autoscalingv1alpha1.VolumeAutoscalerSpec{
-					IncreasePercent: 10,
-					IncreaseMinimum: &minIncrease,
-					MaxSize:         resource.MustParse("100Gi"),
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Growth: autoscalingv1beta1.GrowthSpec{
+						IncreasePercent: 10,
+						MinIncrement:    &minIncrease,
+					},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
 				},
 			}
 			currentSize := resource.MustParse("10Gi")
@@ -183,10 +380,10 @@ var _ = Describe("VolumeAutoscaler Controller", func() {
 
 		It("should cap at maxSize", func() {
 			reconciler := &VolumeAutoscalerReconciler{}
-			va := &autoscalingv1alpha1.VolumeAutoscaler{
-				Spec: autoscalingv1alpha1.VolumeAutoscalerSpec{
-					IncreasePercent: 50,
-					MaxSize:         resource.MustParse("12Gi"),
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Growth: autoscalingv1beta1.GrowthSpec{IncreasePercent: 50},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("12Gi")},
 				},
 			}
 			currentSize := resource.MustParse("10Gi")
@@ -198,10 +395,10 @@ var _ = Describe("VolumeAutoscaler Controller", func() {
 
 		It("should default to 1Gi minimum when increaseMinimum not set", func() {
 			reconciler := &VolumeAutoscalerReconciler{}
-			va := &autoscalingv1alpha1.VolumeAutoscaler{
-				Spec: autoscalingv1alpha1.VolumeAutoscalerSpec{
-					IncreasePercent: 1, // 1% of 2Gi = 0.02Gi < 1Gi
-					MaxSize:         resource.MustParse("100Gi"),
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Growth: autoscalingv1beta1.GrowthSpec{IncreasePercent: 1}, // 1% of 2Gi = 0.02Gi < 1Gi
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
 				},
 			}
 			currentSize := resource.MustParse("2Gi")
@@ -210,6 +407,826 @@ var _ = Describe("VolumeAutoscaler Controller", func() {
 			expected := resource.MustParse("3Gi")
 			Expect(newSize.Cmp(expected)).To(Equal(0))
 		})
+
+		It("should use emergencyIncreasePercent for emergency expansions", func() {
+			reconciler := &VolumeAutoscalerReconciler{}
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Growth: autoscalingv1beta1.GrowthSpec{
+						IncreasePercent:          20,
+						EmergencyIncreasePercent: 100,
+					},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
+				},
+			}
+			currentSize := resource.MustParse("10Gi")
+			newSize := reconciler.calculateEmergencySize(va, &currentSize)
+			// 10Gi + 100% = 20Gi
+			expected := resource.MustParse("20Gi")
+			Expect(newSize.Cmp(expected)).To(Equal(0))
+		})
+
+		It("should default emergency increase to 50% and still cap at maxSize", func() {
+			reconciler := &VolumeAutoscalerReconciler{}
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("14Gi")},
+				},
+			}
+			currentSize := resource.MustParse("10Gi")
+			newSize := reconciler.calculateEmergencySize(va, &currentSize)
+			// 10Gi + 50% = 15Gi, but max is 14Gi
+			expected := resource.MustParse("14Gi")
+			Expect(newSize.Cmp(expected)).To(Equal(0))
+		})
+
+		It("should pick the increase percentage from the largest step reached", func() {
+			reconciler := &VolumeAutoscalerReconciler{}
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Growth: autoscalingv1beta1.GrowthSpec{
+						IncreasePercent: 50,
+						Steps: []autoscalingv1beta1.GrowthStep{
+							{AboveSize: resource.MustParse("100Gi"), IncreasePercent: 10},
+							{AboveSize: resource.MustParse("50Gi"), IncreasePercent: 20},
+						},
+					},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("1Ti")},
+				},
+			}
+			// Below every step: increasePercent
+			currentSize := resource.MustParse("10Gi")
+			newSize := reconciler.calculateNewSize(va, &currentSize)
+			Expect(newSize.Cmp(resource.MustParse("15Gi"))).To(Equal(0))
+			// 60Gi + 20% = 72Gi
+			currentSize = resource.MustParse("60Gi")
+			newSize = reconciler.calculateNewSize(va, &currentSize)
+			Expect(newSize.Cmp(resource.MustParse("72Gi"))).To(Equal(0))
+			// 200Gi + 10% = 220Gi
+			currentSize = resource.MustParse("200Gi")
+			newSize = reconciler.calculateNewSize(va, &currentSize)
+			Expect(newSize.Cmp(resource.MustParse("220Gi"))).To(Equal(0))
+		})
+
+		It("should cap the increase at maxIncrement, emergencies included", func() {
+			reconciler := &VolumeAutoscalerReconciler{}
+			maxIncrement := resource.MustParse("50Gi")
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Growth: autoscalingv1beta1.GrowthSpec{
+						IncreasePercent:          20,
+						EmergencyIncreasePercent: 50,
+						MaxIncrement:             &maxIncrement,
+					},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("10Ti")},
+				},
+			}
+			// 1Ti + 20% would add ~205Gi, capped to 50Gi
+			currentSize := resource.MustParse("1Ti")
+			newSize := reconciler.calculateNewSize(va, &currentSize)
+			Expect(newSize.Cmp(resource.MustParse("1074Gi"))).To(Equal(0))
+			newSize = reconciler.calculateEmergencySize(va, &currentSize)
+			Expect(newSize.Cmp(resource.MustParse("1074Gi"))).To(Equal(0))
+		})
+	})
+
+	Context("When suspended", func() {
+		It("should skip polling and report Suspended", func() {
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "suspended-va",
+					Namespace: vaNamespace,
+				},
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Suspend: true,
+					Target: autoscalingv1beta1.VolumeAutoscalerTarget{
+						PVCName: "nonexistent-pvc",
+					},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
+					Source: autoscalingv1beta1.SourceSpec{Prometheus: autoscalingv1beta1.PrometheusSource{URL: promServer.URL}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, va)).To(Succeed())
+
+			reconciler := &VolumeAutoscalerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: events.NewFakeRecorder(10),
+			}
+			key := types.NamespacedName{Name: "suspended-va", Namespace: vaNamespace}
+			defer func() {
+				Expect(k8sClient.Delete(ctx, va)).To(Succeed())
+				_, _ = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			}()
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+			updatedVA := &autoscalingv1beta1.VolumeAutoscaler{}
+			Expect(k8sClient.Get(ctx, key, updatedVA)).To(Succeed())
+			Expect(updatedVA.Status.Conditions).NotTo(BeEmpty())
+			Expect(updatedVA.Status.Conditions[0].Reason).To(Equal("Suspended"))
+			Expect(updatedVA.Status.LastPollTime).To(BeNil())
+		})
+	})
+
+	Context("When recording decisions", func() {
+		It("should record why a PVC was or was not expanded", func() {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      pvcName,
+					Namespace: vaNamespace,
+					// Expanded a moment ago, so still in cooldown
+					Annotations: map[string]string{
+						AnnotationLastScaleTime: time.Now().UTC().Format(time.RFC3339),
+						AnnotationLastScaleSize: "10Gi",
+					},
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+			defer func() {
+				_ = k8sClient.Delete(ctx, pvc)
+			}()
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "decisions-va", Namespace: vaNamespace},
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Target: autoscalingv1beta1.VolumeAutoscalerTarget{PVCName: pvcName},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
+					Source: autoscalingv1beta1.SourceSpec{Prometheus: autoscalingv1beta1.PrometheusSource{URL: promServer.URL}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, va)).To(Succeed())
+
+			decisions := decision.NewLog(10)
+			reconciler := &VolumeAutoscalerReconciler{
+				Client:    k8sClient,
+				Scheme:    k8sClient.Scheme(),
+				Recorder:  events.NewFakeRecorder(10),
+				Decisions: decisions,
+			}
+			key := types.NamespacedName{Name: "decisions-va", Namespace: vaNamespace}
+			defer func() {
+				Expect(k8sClient.Delete(ctx, va)).To(Succeed())
+				_, _ = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			}()
+
+			// 50% is below the default threshold
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			records := decisions.List(decision.Filter{Namespace: vaNamespace, PVC: pvcName})
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].Action).To(Equal(decision.ActionNone))
+			Expect(records[0].Inputs.UsagePercent).To(Equal(int32(50)))
+			Expect(records[0].Inputs.ThresholdPercent).To(Equal(int32(80)))
+			Expect(records[0].Checks).To(BeEmpty())
+
+			// 95% triggers, but the cooldown holds it back
+			usedBytes = capBytes * 0.95
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			records = decisions.List(decision.Filter{Namespace: vaNamespace, PVC: pvcName, Limit: 1})
+			Expect(records[0].Action).To(Equal(decision.ActionSkipped))
+			Expect(records[0].TriggeredBy).To(Equal([]string{TriggerThresholdPercent}))
+			Expect(records[0].Checks).NotTo(BeEmpty())
+			last := records[0].Checks[len(records[0].Checks)-1]
+			Expect(last.Name).To(Equal(CheckCooldown))
+			Expect(last.Passed).To(BeFalse())
+			Expect(last.Reason).To(ContainSubstring("cooldown"))
+			Expect(records[0].Reason).To(Equal(last.Reason))
+			Expect(records[0].NewSize).To(BeEmpty())
+		})
+	})
+
+	Context("When a volume is unhealthy", func() {
+		It("should block the expansion and report it on the PVC", func() {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: vaNamespace},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+			defer func() {
+				_ = k8sClient.Delete(ctx, pvc)
+			}()
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "unhealthy-va", Namespace: vaNamespace},
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Target: autoscalingv1beta1.VolumeAutoscalerTarget{PVCName: pvcName},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
+					Source: autoscalingv1beta1.SourceSpec{Prometheus: autoscalingv1beta1.PrometheusSource{URL: promServer.URL}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, va)).To(Succeed())
+
+			recorder := events.NewFakeRecorder(10)
+			reconciler := &VolumeAutoscalerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+				HealthCheckers: []health.Checker{staticHealth{err: &health.UnhealthyError{
+					Source: "Longhorn", Reason: "Degraded", Message: "volume pvc-123 is degraded",
+				}}},
+			}
+			key := types.NamespacedName{Name: "unhealthy-va", Namespace: vaNamespace}
+			defer func() {
+				Expect(k8sClient.Delete(ctx, va)).To(Succeed())
+				_, _ = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			}()
+
+			usedBytes = capBytes * 0.95
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			updatedPVC := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: vaNamespace}, updatedPVC)).To(Succeed())
+			Expect(updatedPVC.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("10Gi")))
+
+			updatedVA := &autoscalingv1beta1.VolumeAutoscaler{}
+			Expect(k8sClient.Get(ctx, key, updatedVA)).To(Succeed())
+			Expect(updatedVA.Status.PVCs).To(HaveLen(1))
+			Expect(updatedVA.Status.PVCs[0].Unhealthy).To(Equal(&autoscalingv1beta1.PVCHealth{
+				Source: "Longhorn", Reason: "Degraded", Message: "volume pvc-123 is degraded",
+			}))
+			Expect(meta.IsStatusConditionTrue(updatedVA.Status.Conditions, conditionVolumeUnhealthy)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(ContainSubstring("VolumeUnhealthy")))
+
+			// Recovered: the report and the condition clear
+			reconciler.HealthCheckers = []health.Checker{staticHealth{}}
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, updatedVA)).To(Succeed())
+			Expect(updatedVA.Status.PVCs[0].Unhealthy).To(BeNil())
+			Expect(meta.IsStatusConditionTrue(updatedVA.Status.Conditions, conditionVolumeUnhealthy)).To(BeFalse())
+		})
+	})
+
+	Context("When a resize limit is exhausted", func() {
+		It("should queue the expansion and ask again when a slot frees up", func() {
+			expandable := true
+			sc := &storagev1.StorageClass{
+				ObjectMeta:           metav1.ObjectMeta{Name: "limited"},
+				Provisioner:          "csi.example.com",
+				AllowVolumeExpansion: &expandable,
+			}
+			Expect(k8sClient.Create(ctx, sc)).To(Succeed())
+			defer func() {
+				_ = k8sClient.Delete(ctx, sc)
+			}()
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: vaNamespace},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					StorageClassName: &sc.Name,
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+			defer func() {
+				_ = k8sClient.Delete(ctx, pvc)
+			}()
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "limited-va", Namespace: vaNamespace},
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Target: autoscalingv1beta1.VolumeAutoscalerTarget{PVCName: pvcName},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
+					Source: autoscalingv1beta1.SourceSpec{Prometheus: autoscalingv1beta1.PrometheusSource{URL: promServer.URL}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, va)).To(Succeed())
+
+			cfg := config.Default()
+			cfg.ResizeLimits = []config.ResizeLimit{{Driver: "csi.example.com", PerMinute: 6, Burst: 1}}
+			clk := clocktesting.NewFakePassiveClock(time.Now())
+			limiter := ratelimit.New()
+			// Another PVC of the same driver takes the only token
+			Expect(limiter.Admit(clk.Now(), cfg.ResizeLimits[0], types.NamespacedName{Namespace: "other", Name: "data"},
+				0.9, time.Minute).Admitted).To(BeTrue())
+			decisions := decision.NewLog(10)
+			reconciler := &VolumeAutoscalerReconciler{
+				Client:        k8sClient,
+				Scheme:        k8sClient.Scheme(),
+				Recorder:      events.NewFakeRecorder(10),
+				Config:        config.NewStaticStore(cfg),
+				Clock:         clk,
+				Decisions:     decisions,
+				ResizeLimiter: limiter,
+			}
+			key := types.NamespacedName{Name: "limited-va", Namespace: vaNamespace}
+			defer func() {
+				Expect(k8sClient.Delete(ctx, va)).To(Succeed())
+				_, _ = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			}()
+
+			usedBytes = capBytes * 0.95
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+
+			updatedPVC := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: vaNamespace}, updatedPVC)).To(Succeed())
+			Expect(updatedPVC.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("10Gi")))
+			records := decisions.List(decision.Filter{VolumeAutoscaler: "limited-va"})
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].Action).To(Equal(decision.ActionQueued))
+			Expect(limiter.Queued(cfg.ResizeLimits[0])).To(Equal(1))
+
+			// The token has refilled: the queued expansion goes through
+			clk.SetTime(clk.Now().Add(10 * time.Second))
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: vaNamespace}, updatedPVC)).To(Succeed())
+			Expect(updatedPVC.Spec.Resources.Requests[corev1.ResourceStorage]).NotTo(Equal(resource.MustParse("10Gi")))
+			Expect(limiter.Queued(cfg.ResizeLimits[0])).To(BeZero())
+		})
+	})
+
+	Context("When usage crosses the emergency threshold", func() {
+		It("should expand during the cooldown and still cap at maxSize", func() {
+			expandable := true
+			sc := &storagev1.StorageClass{
+				ObjectMeta:           metav1.ObjectMeta{Name: "emergency"},
+				Provisioner:          "csi.example.com",
+				AllowVolumeExpansion: &expandable,
+			}
+			Expect(k8sClient.Create(ctx, sc)).To(Succeed())
+			defer func() {
+				_ = k8sClient.Delete(ctx, sc)
+			}()
+			clk := clocktesting.NewFakePassiveClock(time.Now().Truncate(time.Second))
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: vaNamespace, Annotations: map[string]string{
+					// Expanded a minute ago: well inside the cooldown
+					AnnotationLastScaleTime: clk.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+					AnnotationLastScaleSize: "10Gi",
+				}},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					StorageClassName: &sc.Name,
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+			defer func() {
+				_ = k8sClient.Delete(ctx, pvc)
+			}()
+			pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}
+			Expect(k8sClient.Status().Update(ctx, pvc)).To(Succeed())
+
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "emergency-va", Namespace: vaNamespace},
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Target:  autoscalingv1beta1.VolumeAutoscalerTarget{PVCName: pvcName},
+					Trigger: autoscalingv1beta1.TriggerSpec{ThresholdPercent: 80, EmergencyThresholdPercent: 90},
+					Growth: autoscalingv1beta1.GrowthSpec{
+						EmergencyIncreasePercent: 50,
+						CooldownPeriod:           &metav1.Duration{Duration: time.Hour},
+					},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("12Gi")},
+					Source: autoscalingv1beta1.SourceSpec{Prometheus: autoscalingv1beta1.PrometheusSource{URL: promServer.URL}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, va)).To(Succeed())
+
+			recorder := events.NewFakeRecorder(20)
+			reconciler := &VolumeAutoscalerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+				Clock:    clk,
+			}
+			key := types.NamespacedName{Name: "emergency-va", Namespace: vaNamespace}
+			defer func() {
+				Expect(k8sClient.Delete(ctx, va)).To(Succeed())
+				_, _ = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			}()
+
+			// Above the threshold but below the emergency threshold: the
+			// cooldown holds
+			usedBytes = capBytes * 0.85
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			updatedPVC := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: vaNamespace}, updatedPVC)).To(Succeed())
+			Expect(updatedPVC.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("10Gi")))
+
+			// 10Gi + 50% = 15Gi, capped at maxSize
+			usedBytes = capBytes * 0.95
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: vaNamespace}, updatedPVC)).To(Succeed())
+			newSize := updatedPVC.Spec.Resources.Requests[corev1.ResourceStorage]
+			Expect(newSize.Cmp(resource.MustParse("12Gi"))).To(Equal(0))
+
+			var emitted []string
+			for len(recorder.Events) > 0 {
+				emitted = append(emitted, <-recorder.Events)
+			}
+			Expect(emitted).To(ContainElement(ContainSubstring("EmergencyExpanded")))
+		})
+	})
+
+	Context("When planning an expansion", func() {
+		newPVC := func(size string, annotations map[string]string) *corev1.PersistentVolumeClaim {
+			return &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: vaNamespace, Annotations: annotations},
+				Status: corev1.PersistentVolumeClaimStatus{
+					Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			}
+		}
+		newVA := func() *autoscalingv1beta1.VolumeAutoscaler {
+			return &autoscalingv1beta1.VolumeAutoscaler{
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Trigger: autoscalingv1beta1.TriggerSpec{EmergencyThresholdPercent: 95},
+					Growth:  autoscalingv1beta1.GrowthSpec{IncreasePercent: 20},
+					Limits:  autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
+				},
+			}
+		}
+
+		It("should not act below the threshold", func() {
+			plan := PlanExpansion(ctx, k8sClient, nil, nil, newVA(), newPVC("10Gi", nil),
+				autoscalingv1beta1.PVCStatus{}, 50)
+			Expect(plan.Mode).To(BeEmpty())
+			Expect(plan.ThresholdPercent).To(Equal(int32(80)))
+			Expect(plan.NewSize).To(BeNil())
+		})
+
+		It("should report the cooldown as blocking a normal expansion", func() {
+			lastScale := metav1.Now()
+			plan := PlanExpansion(ctx, k8sClient, nil, nil, newVA(), newPVC("10Gi", nil),
+				autoscalingv1beta1.PVCStatus{LastScaleTime: &lastScale}, 85)
+			Expect(plan.Mode).To(Equal("normal"))
+			Expect(plan.Blocked).To(ContainSubstring("cooldown"))
+			Expect(plan.NewSize).To(BeNil())
+		})
+
+		It("should ignore threshold and cooldown for a forced expansion", func() {
+			lastScale := metav1.Now()
+			pvc := newPVC("10Gi", map[string]string{AnnotationExpandNow: "2026-01-01T00:00:00Z"})
+			plan := PlanExpansion(ctx, k8sClient, nil, nil, newVA(), pvc,
+				autoscalingv1beta1.PVCStatus{LastScaleTime: &lastScale}, 10)
+			Expect(plan.Mode).To(Equal("forced"))
+			Expect(plan.Blocked).To(BeEmpty())
+			Expect(plan.NewSize.String()).To(Equal("12Gi"))
+		})
+
+		It("should still block a forced expansion at maxSize", func() {
+			pvc := newPVC("100Gi", map[string]string{AnnotationExpandNow: "2026-01-01T00:00:00Z"})
+			plan := PlanExpansion(ctx, k8sClient, nil, nil, newVA(), pvc, autoscalingv1beta1.PVCStatus{}, 10)
+			Expect(plan.Blocked).To(ContainSubstring("maxSize"))
+		})
+
+		It("should use the emergency increase above the emergency threshold", func() {
+			plan := PlanExpansion(ctx, k8sClient, nil, nil, newVA(), newPVC("10Gi", nil),
+				autoscalingv1beta1.PVCStatus{}, 96)
+			Expect(plan.Mode).To(Equal("emergency"))
+			Expect(plan.NewSize.String()).To(Equal("15Gi"))
+		})
+
+		It("should expand below minFreeBytes while under the percentage threshold", func() {
+			va := newVA()
+			minFree := resource.MustParse("50Gi")
+			va.Spec.Trigger.MinFreeBytes = &minFree
+			// 60% of 100Gi leaves 40Gi free
+			status := autoscalingv1beta1.PVCStatus{UsageBytes: 60 << 30, FreeBytes: 40 << 30, UsagePercent: 60}
+			plan := PlanExpansion(ctx, k8sClient, nil, nil, va, newPVC("50Gi", nil), status, 60)
+			Expect(plan.Mode).To(Equal("normal"))
+			Expect(plan.TriggeredBy).To(Equal([]string{TriggerMinFreeBytes}))
+
+			va.Spec.Trigger.Mode = autoscalingv1beta1.ThresholdModeAll
+			plan = PlanExpansion(ctx, k8sClient, nil, nil, va, newPVC("50Gi", nil), status, 60)
+			Expect(plan.Mode).To(BeEmpty())
+		})
+
+		It("should require both criteria in all mode", func() {
+			va := newVA()
+			minFree := resource.MustParse("2Gi")
+			va.Spec.Trigger.MinFreeBytes = &minFree
+			va.Spec.Trigger.Mode = autoscalingv1beta1.ThresholdModeAll
+			// 85% of 100Gi leaves 15Gi free, well above the floor
+			status := autoscalingv1beta1.PVCStatus{UsageBytes: 85 << 30, FreeBytes: 15 << 30, UsagePercent: 85}
+			Expect(PlanExpansion(ctx, k8sClient, nil, nil, va, newPVC("10Gi", nil), status, 85).Mode).To(BeEmpty())
+
+			// at 99% only 1Gi is left, so both fire
+			plan := PlanExpansion(ctx, k8sClient, nil, nil, va, newPVC("10Gi", nil), status, 99)
+			Expect(plan.Mode).To(Equal("emergency"))
+			Expect(plan.TriggeredBy).To(Equal([]string{TriggerThresholdPercent, TriggerMinFreeBytes, TriggerEmergency}))
+		})
+
+		It("should ignore minFreeBytes when free space was never recorded", func() {
+			va := newVA()
+			minFree := resource.MustParse("50Gi")
+			va.Spec.Trigger.MinFreeBytes = &minFree
+			plan := PlanExpansion(ctx, k8sClient, nil, nil, va, newPVC("10Gi", nil), autoscalingv1beta1.PVCStatus{}, 50)
+			Expect(plan.Mode).To(BeEmpty())
+		})
+
+		It("should cap at the per-PVC maxSize override", func() {
+			pvc := newPVC("10Gi", map[string]string{AnnotationMaxSize: "11Gi"})
+			plan := PlanExpansion(ctx, k8sClient, nil, nil, newVA(), pvc, autoscalingv1beta1.PVCStatus{}, 85)
+			Expect(plan.Blocked).To(BeEmpty())
+			Expect(plan.NewSize.String()).To(Equal("11Gi"))
+		})
+
+		It("should allow the override to raise maxSize", func() {
+			pvc := newPVC("100Gi", map[string]string{AnnotationMaxSize: "200Gi"})
+			plan := PlanExpansion(ctx, k8sClient, nil, nil, newVA(), pvc, autoscalingv1beta1.PVCStatus{}, 85)
+			Expect(plan.Blocked).To(BeEmpty())
+			Expect(plan.NewSize.String()).To(Equal("120Gi"))
+		})
+
+		It("should block on an invalid maxSize override", func() {
+			pvc := newPVC("10Gi", map[string]string{AnnotationMaxSize: "lots"})
+			plan := PlanExpansion(ctx, k8sClient, nil, nil, newVA(), pvc, autoscalingv1beta1.PVCStatus{}, 85)
+			Expect(plan.Blocked).To(ContainSubstring(AnnotationMaxSize))
+			Expect(plan.NewSize).To(BeNil())
+		})
+
+		It("should hold the cooldown from the PVC's scale record when status was lost", func() {
+			pvc := newPVC("12Gi", map[string]string{
+				AnnotationLastScaleTime: time.Now().UTC().Format(time.RFC3339),
+				AnnotationLastScaleSize: "12Gi",
+			})
+			plan := PlanExpansion(ctx, k8sClient, nil, nil, newVA(), pvc, autoscalingv1beta1.PVCStatus{}, 85)
+			Expect(plan.Blocked).To(ContainSubstring("cooldown"))
+		})
+
+		It("should read the scale record into the PVC status", func() {
+			pvc := newPVC("12Gi", map[string]string{
+				AnnotationLastScaleTime: "2026-01-01T00:00:00Z",
+				AnnotationLastScaleSize: "12Gi",
+			})
+			var status autoscalingv1beta1.PVCStatus
+			applyScaleRecord(pvc, &status)
+			Expect(status.LastScaleTime.UTC()).To(Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+			Expect(status.LastScaleSize.String()).To(Equal("12Gi"))
+
+			By("ignoring an unparsable record")
+			lastScale := metav1.Now()
+			status = autoscalingv1beta1.PVCStatus{LastScaleTime: &lastScale}
+			pvc.Annotations[AnnotationLastScaleTime] = "yesterday"
+			applyScaleRecord(pvc, &status)
+			Expect(status.LastScaleTime).To(Equal(&lastScale))
+		})
+	})
+
+	Context("When detecting anomalies", func() {
+		var (
+			reconciler *VolumeAutoscalerReconciler
+			recorder   *events.FakeRecorder
+			pvc        *corev1.PersistentVolumeClaim
+			va         *autoscalingv1beta1.VolumeAutoscaler
+		)
+		const capBytes = 10 * 1024 * 1024 * 1024
+
+		BeforeEach(func() {
+			recorder = events.NewFakeRecorder(10)
+			reconciler = &VolumeAutoscalerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+			pvc = &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "anomaly-pvc", Namespace: vaNamespace},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+			va = &autoscalingv1beta1.VolumeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "anomaly-va", Namespace: vaNamespace},
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					AnomalyDetection: &autoscalingv1beta1.AnomalyDetection{
+						MaxJumpPercent:      20,
+						MaxExpansionsPerDay: 3,
+						SuppressExpansion:   true,
+					},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, pvc)).To(Succeed())
+		})
+
+		It("should flag a jump within one poll and suppress expansion", func() {
+			prev := &autoscalingv1beta1.PVCStatus{Name: pvc.Name, UsageBytes: capBytes / 10}
+			cur := &autoscalingv1beta1.PVCStatus{Name: pvc.Name, UsageBytes: capBytes / 2}
+
+			Expect(reconciler.checkAnomalies(ctx, va, pvc, prev, cur, capBytes, nil)).To(BeTrue())
+			Expect(cur.Anomaly).NotTo(BeNil())
+			Expect(cur.Anomaly.Kind).To(Equal("Jump"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("AnomalousGrowth")))
+		})
+
+		It("should not flag steady growth", func() {
+			prev := &autoscalingv1beta1.PVCStatus{Name: pvc.Name, UsageBytes: capBytes / 10}
+			cur := &autoscalingv1beta1.PVCStatus{Name: pvc.Name, UsageBytes: capBytes / 5}
+
+			Expect(reconciler.checkAnomalies(ctx, va, pvc, prev, cur, capBytes, nil)).To(BeFalse())
+			Expect(cur.Anomaly).To(BeNil())
+		})
+
+		It("should flag repeated expansions within a day", func() {
+			now := metav1.Now()
+			cur := &autoscalingv1beta1.PVCStatus{
+				Name:             pvc.Name,
+				RecentExpansions: expansionsAt(now, 4),
+			}
+
+			Expect(reconciler.checkAnomalies(ctx, va, pvc, nil, cur, capBytes, nil)).To(BeTrue())
+			Expect(cur.Anomaly.Kind).To(Equal("ExpansionRate"))
+		})
+
+		It("should flag a PVC projected to reach maxSize within a day", func() {
+			va.Spec.AnomalyDetection = &autoscalingv1beta1.AnomalyDetection{ProjectMaxSize: true}
+			va.Spec.Limits.MaxSize = resource.MustParse("14Gi")
+			now := metav1.Now()
+			// 10Gi -> 11Gi twice: 2Gi a day puts 12Gi at maxSize tomorrow
+			cur := &autoscalingv1beta1.PVCStatus{
+				Name:             pvc.Name,
+				CurrentSize:      resource.MustParse("12Gi"),
+				RecentExpansions: expansionsAt(now, 2),
+			}
+
+			Expect(reconciler.checkAnomalies(ctx, va, pvc, nil, cur, capBytes, nil)).To(BeFalse())
+			Expect(cur.Anomaly).NotTo(BeNil())
+			Expect(cur.Anomaly.Kind).To(Equal("MaxSizeProjected"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("reaches maxSize 14Gi within a day")))
+		})
+
+		It("should not flag growth that stays below maxSize for a day", func() {
+			va.Spec.AnomalyDetection = &autoscalingv1beta1.AnomalyDetection{ProjectMaxSize: true}
+			va.Spec.Limits.MaxSize = resource.MustParse("100Gi")
+			cur := &autoscalingv1beta1.PVCStatus{
+				Name:             pvc.Name,
+				CurrentSize:      resource.MustParse("12Gi"),
+				RecentExpansions: expansionsAt(metav1.Now(), 2),
+			}
+
+			Expect(reconciler.checkAnomalies(ctx, va, pvc, nil, cur, capBytes, nil)).To(BeFalse())
+			Expect(cur.Anomaly).To(BeNil())
+		})
+
+		It("should strip an acknowledgement with detection disabled", func() {
+			pvc.Annotations = map[string]string{AnnotationAnomalyAck: "2026-01-01T00:00:00Z"}
+			Expect(k8sClient.Update(ctx, pvc)).To(Succeed())
+			va.Spec.AnomalyDetection = nil
+			cur := &autoscalingv1beta1.PVCStatus{Name: pvc.Name}
+
+			Expect(reconciler.checkAnomalies(ctx, va, pvc, nil, cur, capBytes, nil)).To(BeFalse())
+			updated := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: pvc.Name, Namespace: vaNamespace}, updated)).To(Succeed())
+			Expect(updated.Annotations).NotTo(HaveKey(AnnotationAnomalyAck))
+		})
+
+		It("should report without suppressing when suppressExpansion is off", func() {
+			va.Spec.AnomalyDetection.SuppressExpansion = false
+			prev := &autoscalingv1beta1.PVCStatus{Name: pvc.Name, UsageBytes: capBytes / 10}
+			cur := &autoscalingv1beta1.PVCStatus{Name: pvc.Name, UsageBytes: capBytes / 2}
+
+			Expect(reconciler.checkAnomalies(ctx, va, pvc, prev, cur, capBytes, nil)).To(BeFalse())
+			Expect(cur.Anomaly).NotTo(BeNil())
+		})
+
+		It("should clear an acknowledged anomaly and pause detection", func() {
+			pvc.Annotations = map[string]string{AnnotationAnomalyAck: "2026-01-01T00:00:00Z"}
+			Expect(k8sClient.Update(ctx, pvc)).To(Succeed())
+
+			now := metav1.Now()
+			cur := &autoscalingv1beta1.PVCStatus{
+				Name:             pvc.Name,
+				RecentExpansions: expansionsAt(now, 4),
+				Anomaly:          &autoscalingv1beta1.PVCAnomaly{Kind: "ExpansionRate", DetectedTime: now},
+			}
+
+			Expect(reconciler.checkAnomalies(ctx, va, pvc, nil, cur, capBytes, nil)).To(BeFalse())
+			Expect(cur.Anomaly).To(BeNil())
+			Expect(cur.AnomalyAcknowledgedTime).NotTo(BeNil())
+			Expect(recorder.Events).To(Receive(ContainSubstring("AnomalyAcknowledged")))
+
+			updated := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: pvc.Name, Namespace: vaNamespace}, updated)).To(Succeed())
+			Expect(updated.Annotations).NotTo(HaveKey(AnnotationAnomalyAck))
+		})
+
+		It("should only count expansions from the last 24 hours", func() {
+			cur := &autoscalingv1beta1.PVCStatus{
+				Name:             pvc.Name,
+				RecentExpansions: expansionsAt(metav1.NewTime(time.Now().Add(-25*time.Hour)), 4),
+			}
+
+			Expect(reconciler.checkAnomalies(ctx, va, pvc, nil, cur, capBytes, nil)).To(BeFalse())
+			Expect(cur.Anomaly).To(BeNil())
+		})
+	})
+
+	Context("When reporting cost", func() {
+		sc := "harvester"
+		newPVC := func(name, size string) corev1.PersistentVolumeClaim {
+			return corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: vaNamespace},
+				Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &sc},
+				Status: corev1.PersistentVolumeClaimStatus{
+					Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			}
+		}
+
+		It("should price provisioned and autoscaled capacity by StorageClass", func() {
+			now := metav1.Now()
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "cost-va", Namespace: vaNamespace},
+				Status: autoscalingv1beta1.VolumeAutoscalerStatus{PVCs: []autoscalingv1beta1.PVCStatus{{
+					Name: "a",
+					RecentExpansions: []autoscalingv1beta1.PVCExpansion{
+						{Time: now, From: resource.MustParse("80Gi"), To: resource.MustParse("100Gi")},
+					},
+				}}},
+			}
+			unpriced := newPVC("b", "50Gi")
+			unpriced.Spec.StorageClassName = nil
+			cfg := config.Default()
+			cfg.Currency = "EUR"
+			cfg.StorageClassPrices = map[string]float64{sc: 0.1}
+
+			updateCost(va, []corev1.PersistentVolumeClaim{newPVC("a", "100Gi"), unpriced}, cfg)
+
+			Expect(va.Status.Cost).NotTo(BeNil())
+			Expect(va.Status.Cost.Currency).To(Equal("EUR"))
+			Expect(va.Status.Cost.ProvisionedMonthly).To(Equal("10.00"))
+			Expect(va.Status.Cost.AddedLast30DaysMonthly).To(Equal("2.00"))
+			Expect(va.Status.Cost.UnpricedPVCs).To(Equal(int32(1)))
+		})
+
+		It("should clear cost when no prices are configured", func() {
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				Status: autoscalingv1beta1.VolumeAutoscalerStatus{
+					Cost: &autoscalingv1beta1.CostStatus{ProvisionedMonthly: "1.00"},
+				},
+			}
+			updateCost(va, []corev1.PersistentVolumeClaim{newPVC("a", "10Gi")}, config.Default())
+			Expect(va.Status.Cost).To(BeNil())
+		})
+
+		It("should only keep expansions from the last 30 days", func() {
+			now := time.Now()
+			history := append(
+				expansionsAt(metav1.NewTime(now.Add(-31*24*time.Hour)), 2),
+				expansionsAt(metav1.NewTime(now.Add(-time.Hour)), 1)...,
+			)
+			Expect(recentExpansions(history, now)).To(HaveLen(1))
+			Expect(recentExpansions(expansionsAt(metav1.NewTime(now), 250), now)).To(HaveLen(maxExpansionHistory - 1))
+		})
+	})
+
+	Context("When sharding", func() {
+		It("should handle every VolumeAutoscaler when no shard is set", func() {
+			reconciler := &VolumeAutoscalerReconciler{}
+			va := &autoscalingv1beta1.VolumeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{ShardLabel: "tenant-a"},
+				},
+			}
+			Expect(reconciler.inShard(va)).To(BeTrue())
+			Expect(reconciler.inShard(&autoscalingv1beta1.VolumeAutoscaler{})).To(BeTrue())
+		})
+
+		It("should only handle VolumeAutoscalers labeled for its shard", func() {
+			reconciler := &VolumeAutoscalerReconciler{Shard: "tenant-a"}
+			mine := &autoscalingv1beta1.VolumeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{ShardLabel: "tenant-a"},
+				},
+			}
+			other := &autoscalingv1beta1.VolumeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{ShardLabel: "tenant-b"},
+				},
+			}
+			Expect(reconciler.inShard(mine)).To(BeTrue())
+			Expect(reconciler.inShard(other)).To(BeFalse())
+			Expect(reconciler.inShard(&autoscalingv1beta1.VolumeAutoscaler{})).To(BeFalse())
+		})
 	})
 
 	Context("When resolving PVCs", func() {
@@ -242,16 +1259,16 @@ var _ = Describe("VolumeAutoscaler Controller", func() {
 				Scheme: k8sClient.Scheme(),
 			}
 
-			va := &autoscalingv1alpha1.VolumeAutoscaler{
+			va := &autoscalingv1beta1.VolumeAutoscaler{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      vaName,
 					Namespace: vaNamespace,
 				},
-				Spec: autoscalingv1alpha1.VolumeAutoscalerSpec{
-					Target: autoscalingv1alpha1.VolumeAutoscalerTarget{
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Target: autoscalingv1beta1.VolumeAutoscalerTarget{
 						PVCName: pvcName,
 					},
-					MaxSize: resource.MustParse("100Gi"),
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
 				},
 			}
 
@@ -308,24 +1325,33 @@ var _ = Describe("VolumeAutoscaler Controller", func() {
 				Scheme: k8sClient.Scheme(),
 			}
 
-			va := &autoscalingv1alpha1.VolumeAutoscaler{
+			va := &autoscalingv1beta1.VolumeAutoscaler{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      vaName,
 					Namespace: vaNamespace,
 				},
-				Spec: autoscalingv1alpha1.VolumeAutoscalerSpec{
-					Target: autoscalingv1alpha1.VolumeAutoscalerTarget{
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Target: autoscalingv1beta1.VolumeAutoscalerTarget{
 						Selector: &metav1.LabelSelector{
 							MatchLabels: map[string]string{"app": "test-selector"},
 						},
 					},
-					MaxSize: resource.MustParse("100Gi"),
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
 				},
 			}
 
 			pvcs, err := reconciler.resolvePVCs(context.Background(), va)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(pvcs).To(HaveLen(2))
+
+			By("opting one PVC out with the exclude annotation")
+			pvc2.Annotations = map[string]string{AnnotationExclude: "true"}
+			Expect(k8sClient.Update(ctx, pvc2)).To(Succeed())
+
+			pvcs, err = reconciler.resolvePVCs(context.Background(), va)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pvcs).To(HaveLen(1))
+			Expect(pvcs[0].Name).To(Equal("labeled-pvc-1"))
 		})
 
 		It("should error when no target is specified", func() {
@@ -334,26 +1360,230 @@ var _ = Describe("VolumeAutoscaler Controller", func() {
 				Scheme: k8sClient.Scheme(),
 			}
 
-			va := &autoscalingv1alpha1.VolumeAutoscaler{
+			va := &autoscalingv1beta1.VolumeAutoscaler{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      vaName,
 					Namespace: vaNamespace,
 				},
-				Spec: autoscalingv1alpha1.VolumeAutoscalerSpec{
-					Target:  autoscalingv1alpha1.VolumeAutoscalerTarget{},
-					MaxSize: resource.MustParse("100Gi"),
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Target: autoscalingv1beta1.VolumeAutoscalerTarget{},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
 				},
 			}
 
 			_, err := reconciler.resolvePVCs(context.Background(), va)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("must specify either pvcName or selector"))
+			Expect(err.Error()).To(ContainSubstring("must specify one of pvcName, selector or ownerRef"))
+		})
+	})
+
+	Context("When resolving PVCs by owner", func() {
+		newOwnedPVC := func(name string, owners ...metav1.OwnerReference) *corev1.PersistentVolumeClaim {
+			return &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            name,
+					Namespace:       vaNamespace,
+					OwnerReferences: owners,
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")},
+					},
+				},
+			}
+		}
+		ownerVA := func(ref autoscalingv1beta1.VolumeAutoscalerOwnerRef) *autoscalingv1beta1.VolumeAutoscaler {
+			return &autoscalingv1beta1.VolumeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: vaName, Namespace: vaNamespace},
+				Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+					Target: autoscalingv1beta1.VolumeAutoscalerTarget{OwnerRef: &ref},
+					Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
+				},
+			}
+		}
+		cleanup := func(objs ...client.Object) {
+			for _, obj := range objs {
+				_ = k8sClient.Delete(ctx, obj)
+			}
+		}
+
+		It("should find PVCs owned directly by another operator's resource", func() {
+			clusterRef := metav1.OwnerReference{
+				APIVersion: "postgresql.cnpg.io/v1", Kind: "Cluster", Name: "keycloak-pg", UID: "uid-cnpg",
+			}
+			owned := newOwnedPVC("keycloak-pg-1", clusterRef)
+			other := newOwnedPVC("other-pg-1", metav1.OwnerReference{
+				APIVersion: "postgresql.cnpg.io/v1", Kind: "Cluster", Name: "other-pg", UID: "uid-other",
+			})
+			Expect(k8sClient.Create(ctx, owned)).To(Succeed())
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+			defer cleanup(owned, other)
+
+			reconciler := &VolumeAutoscalerReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			pvcs, err := reconciler.resolvePVCs(ctx, ownerVA(autoscalingv1beta1.VolumeAutoscalerOwnerRef{
+				APIVersion: "postgresql.cnpg.io/v1", Kind: "Cluster", Name: "keycloak-pg",
+			}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pvcs).To(HaveLen(1))
+			Expect(pvcs[0].Name).To(Equal("keycloak-pg-1"))
+
+			By("ignoring owners from a different API group")
+			pvcs, err = reconciler.resolvePVCs(ctx, ownerVA(autoscalingv1beta1.VolumeAutoscalerOwnerRef{
+				APIVersion: "example.com/v1", Kind: "Cluster", Name: "keycloak-pg",
+			}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pvcs).To(BeEmpty())
+		})
+
+		It("should find StatefulSet template PVCs and ephemeral volumes of owned pods", func() {
+			replicas := int32(1)
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "gitaly", Namespace: vaNamespace},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: &replicas,
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "gitaly"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "gitaly"}},
+						Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "gitaly", Image: "gitaly"}}},
+					},
+					VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+						ObjectMeta: metav1.ObjectMeta{Name: "repo-data"},
+					}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sts)).To(Succeed())
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gitaly-0",
+					Namespace: vaNamespace,
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: "apps/v1", Kind: "StatefulSet", Name: "gitaly", UID: sts.UID,
+					}},
+				},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "gitaly", Image: "gitaly"}}},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+			template := newOwnedPVC("repo-data-gitaly-0")
+			ephemeral := newOwnedPVC("gitaly-0-scratch", metav1.OwnerReference{
+				APIVersion: "v1", Kind: "Pod", Name: "gitaly-0", UID: pod.UID,
+			})
+			lookalike := newOwnedPVC("repo-data-gitaly-backup")
+			Expect(k8sClient.Create(ctx, template)).To(Succeed())
+			Expect(k8sClient.Create(ctx, ephemeral)).To(Succeed())
+			Expect(k8sClient.Create(ctx, lookalike)).To(Succeed())
+			defer cleanup(template, ephemeral, lookalike, pod, sts)
+
+			reconciler := &VolumeAutoscalerReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			pvcs, err := reconciler.resolvePVCs(ctx, ownerVA(autoscalingv1beta1.VolumeAutoscalerOwnerRef{
+				Kind: "StatefulSet", Name: "gitaly",
+			}))
+			Expect(err).NotTo(HaveOccurred())
+			names := []string{}
+			for _, p := range pvcs {
+				names = append(names, p.Name)
+			}
+			Expect(names).To(ConsistOf("repo-data-gitaly-0", "gitaly-0-scratch"))
+		})
+	})
+
+	Context("When an owner reverts an expansion", func() {
+		lastScale := resource.MustParse("12Gi")
+		newPVC := func(request string) *corev1.PersistentVolumeClaim {
+			return &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "data-pg-1",
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: "postgresql.cnpg.io/v1", Kind: "Cluster", Name: "pg",
+					}},
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(request)},
+					},
+				},
+			}
+		}
+
+		It("should detect a request below the last expansion", func() {
+			prev := &autoscalingv1beta1.PVCStatus{LastScaleSize: &lastScale}
+
+			_, reverted := ownerReverted(newPVC("10Gi"), prev)
+			Expect(reverted).To(BeTrue())
+			_, reverted = ownerReverted(newPVC("12Gi"), prev)
+			Expect(reverted).To(BeFalse())
+			_, reverted = ownerReverted(newPVC("10Gi"), nil)
+			Expect(reverted).To(BeFalse())
+		})
+
+		It("should surface OwnerReverted once and clear it when resolved", func() {
+			recorder := events.NewFakeRecorder(10)
+			reconciler := &VolumeAutoscalerReconciler{Recorder: recorder}
+			va := &autoscalingv1beta1.VolumeAutoscaler{}
+
+			By("not adding the condition before any reversion")
+			reconciler.setOwnerReverted(va, nil)
+			Expect(va.Status.Conditions).To(BeEmpty())
+
+			reconciler.setOwnerReverted(va, []string{"data-pg-1 (requested 10Gi, expanded to 12Gi by Cluster/pg)"})
+			cond := meta.FindStatusCondition(va.Status.Conditions, "OwnerReverted")
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Message).To(ContainSubstring("Cluster/pg"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("OwnerReverted")))
+
+			By("not repeating the event while still reverted")
+			reconciler.setOwnerReverted(va, []string{"data-pg-1"})
+			Expect(recorder.Events).NotTo(Receive())
+
+			reconciler.setOwnerReverted(va, nil)
+			Expect(meta.IsStatusConditionFalse(va.Status.Conditions, "OwnerReverted")).To(BeTrue())
 		})
 	})
 
-	_ = errors.IsNotFound // keep import
+	Context("When the backend lacks capacity", func() {
+		It("should surface InsufficientBackendCapacity once and clear it when resolved", func() {
+			recorder := events.NewFakeRecorder(10)
+			reconciler := &VolumeAutoscalerReconciler{Recorder: recorder}
+			va := &autoscalingv1beta1.VolumeAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: vaName, Namespace: vaNamespace}}
+			pvcs := []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "data-0"}}}
+
+			By("not adding the condition before any shortage")
+			reconciler.setInsufficientCapacity(va, pvcs, nil)
+			Expect(va.Status.Conditions).To(BeEmpty())
+
+			short := map[string]string{"data-0": "CSIStorageCapacity has 5Gi available, expansion needs 10Gi"}
+			reconciler.setInsufficientCapacity(va, pvcs, short)
+			cond := meta.FindStatusCondition(va.Status.Conditions, "InsufficientBackendCapacity")
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Message).To(ContainSubstring("data-0: CSIStorageCapacity has 5Gi available"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("InsufficientBackendCapacity")))
+
+			By("not repeating the event while still short")
+			reconciler.setInsufficientCapacity(va, pvcs, short)
+			Expect(recorder.Events).NotTo(Receive())
+
+			reconciler.setInsufficientCapacity(va, pvcs, nil)
+			Expect(meta.IsStatusConditionFalse(va.Status.Conditions, "InsufficientBackendCapacity")).To(BeTrue())
+		})
+	})
 })
 
+// expansionsAt returns n 1Gi expansions recorded at t.
+func expansionsAt(t metav1.Time, n int) []autoscalingv1beta1.PVCExpansion {
+	out := make([]autoscalingv1beta1.PVCExpansion, n)
+	for i := range out {
+		out[i] = autoscalingv1beta1.PVCExpansion{
+			Time: t,
+			From: resource.MustParse("10Gi"),
+			To:   resource.MustParse("11Gi"),
+		}
+	}
+	return out
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsHelper(s, substr))
 }
@@ -366,3 +1596,14 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+// staticHealth is a health.Checker that always returns err.
+type staticHealth struct {
+	err error
+}
+
+func (staticHealth) Name() string { return "static" }
+
+func (s staticHealth) Check(context.Context, client.Reader, *corev1.PersistentVolumeClaim) error {
+	return s.err
+}
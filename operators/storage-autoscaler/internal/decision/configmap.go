@@ -0,0 +1,130 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decision
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ConfigMapKey is the data key the records are written under.
+const ConfigMapKey = "decisions.json"
+
+// maxConfigMapBytes keeps the payload well under the 1MiB object size limit.
+const maxConfigMapBytes = 900 << 10
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update
+
+// ConfigMapWriter periodically copies a Log into a ConfigMap, so the records
+// survive a restart of the manager and can be read with kubectl. The newest
+// records that fit are kept.
+type ConfigMapWriter struct {
+	// Client creates and updates the ConfigMap.
+	Client client.Client
+	// APIReader reads the ConfigMap without starting a cluster-wide informer.
+	APIReader client.Reader
+	Log       *Log
+	// Key names the ConfigMap.
+	Key types.NamespacedName
+	// Interval is the delay between writes. Defaults to 1m.
+	Interval time.Duration
+
+	written uint64
+}
+
+// Start writes until ctx is done. It implements manager.Runnable.
+func (w *ConfigMapWriter) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("decision-log")
+	interval := w.Interval
+	if interval == 0 {
+		interval = time.Minute
+	}
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := w.Write(ctx); err != nil {
+			log.Error(err, "failed to write decision log", "configMap", w.Key)
+		}
+	}, interval)
+	return nil
+}
+
+// NeedLeaderElection makes only the leader, which takes the decisions, write.
+func (w *ConfigMapWriter) NeedLeaderElection() bool {
+	return true
+}
+
+// Write stores the current records in the ConfigMap, creating it if needed.
+// It does nothing if no record was added since the last successful write.
+func (w *ConfigMapWriter) Write(ctx context.Context) error {
+	records, version := w.Log.snapshot(Filter{})
+	if version == w.written {
+		return nil
+	}
+	data, err := encodeWithin(records, maxConfigMapBytes)
+	if err != nil {
+		return err
+	}
+
+	var cm corev1.ConfigMap
+	err = w.APIReader.Get(ctx, w.Key, &cm)
+	switch {
+	case apierrors.IsNotFound(err):
+		cm = corev1.ConfigMap{}
+		cm.Namespace, cm.Name = w.Key.Namespace, w.Key.Name
+		cm.Data = map[string]string{ConfigMapKey: string(data)}
+		if err := w.Client.Create(ctx, &cm); err != nil {
+			return fmt.Errorf("creating ConfigMap %s: %w", w.Key, err)
+		}
+	case err != nil:
+		return fmt.Errorf("getting ConfigMap %s: %w", w.Key, err)
+	default:
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[ConfigMapKey] = string(data)
+		if err := w.Client.Update(ctx, &cm); err != nil {
+			return fmt.Errorf("updating ConfigMap %s: %w", w.Key, err)
+		}
+	}
+	w.written = version
+	return nil
+}
+
+// encodeWithin marshals records, newest first, dropping the oldest until the
+// result is at most limit bytes.
+func encodeWithin(records []Record, limit int) ([]byte, error) {
+	for {
+		data, err := json.Marshal(records)
+		if err != nil {
+			return nil, fmt.Errorf("encoding decision records: %w", err)
+		}
+		if len(data) <= limit || len(records) == 0 {
+			return data, nil
+		}
+		// Estimate how many records fit rather than dropping one at a time
+		keep := len(records) * limit / len(data)
+		records = records[:min(keep, len(records)-1)]
+	}
+}
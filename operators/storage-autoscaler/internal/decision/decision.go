@@ -0,0 +1,180 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package decision keeps a bounded log of the controller's per-PVC decisions,
+// so "why did (or didn't) my PVC grow?" can be answered from one record
+// instead of reconstructed from log lines.
+package decision
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Actions a Record can end in.
+const (
+	// ActionExpanded means the PVC was patched to NewSize.
+	ActionExpanded = "Expanded"
+	// ActionNone means no expansion criterion fired.
+	ActionNone = "None"
+	// ActionSkipped means an expansion was called for but a check blocked it.
+	ActionSkipped = "Skipped"
+	// ActionQueued means the expansion waits for a slot under a resize limit.
+	ActionQueued = "Queued"
+	// ActionFailed means the PVC could not be evaluated or patched.
+	ActionFailed = "Failed"
+)
+
+// Record is the decision taken for one PVC on one poll.
+type Record struct {
+	Time             time.Time `json:"time"`
+	Namespace        string    `json:"namespace"`
+	PVC              string    `json:"pvc"`
+	VolumeAutoscaler string    `json:"volumeAutoscaler"`
+	Inputs           Inputs    `json:"inputs"`
+	// TriggeredBy lists the expansion criteria that fired, as in
+	// PVCStatus.TriggeredBy.
+	TriggeredBy []string `json:"triggeredBy,omitempty"`
+	// Checks holds the outcome of each check run after a trigger, in order.
+	// Evaluation stops at the first failed check.
+	Checks []Check `json:"checks,omitempty"`
+	// NewSize is the computed target size, set once the checks have passed.
+	NewSize string `json:"newSize,omitempty"`
+	// Action is one of the Action* values.
+	Action string `json:"action"`
+	// Reason explains Action when it is not Expanded.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Inputs are the figures a decision was taken on.
+type Inputs struct {
+	UsageBytes                int64  `json:"usageBytes"`
+	CapacityBytes             int64  `json:"capacityBytes"`
+	FreeBytes                 int64  `json:"freeBytes"`
+	UsagePercent              int32  `json:"usagePercent"`
+	CurrentSize               string `json:"currentSize,omitempty"`
+	ThresholdPercent          int32  `json:"thresholdPercent"`
+	MinFreeBytes              string `json:"minFreeBytes,omitempty"`
+	Mode                      string `json:"mode,omitempty"`
+	EmergencyThresholdPercent int32  `json:"emergencyThresholdPercent,omitempty"`
+	InodeUsagePercent         *int32 `json:"inodeUsagePercent,omitempty"`
+	MaxSize                   string `json:"maxSize,omitempty"`
+}
+
+// Check is the outcome of one check.
+type Check struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// AddCheck records the outcome of the named check; a nil err means it passed.
+func (r *Record) AddCheck(name string, err error) {
+	c := Check{Name: name, Passed: err == nil}
+	if err != nil {
+		c.Reason = err.Error()
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+// AddPassedCheck records a check that passed, noting err if the check
+// could only be run in part.
+func (r *Record) AddPassedCheck(name string, err error) {
+	c := Check{Name: name, Passed: true}
+	if err != nil {
+		c.Reason = "not fully checked: " + err.Error()
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+// Filter selects records from a Log. Empty fields match everything.
+type Filter struct {
+	Namespace        string
+	PVC              string
+	VolumeAutoscaler string
+	Action           string
+	// Limit caps the number of records returned; zero returns all matches.
+	Limit int
+}
+
+func (f Filter) matches(r *Record) bool {
+	return (f.Namespace == "" || r.Namespace == f.Namespace) &&
+		(f.PVC == "" || r.PVC == f.PVC) &&
+		(f.VolumeAutoscaler == "" || r.VolumeAutoscaler == f.VolumeAutoscaler) &&
+		(f.Action == "" || r.Action == f.Action)
+}
+
+// Log is a fixed-size ring buffer of records, safe for concurrent use. Once
+// full, each new record replaces the oldest.
+type Log struct {
+	mu      sync.RWMutex
+	records []Record
+	next    int
+	full    bool
+	// version counts additions, so writers can tell whether anything changed.
+	version uint64
+}
+
+// NewLog returns a Log holding up to size records. size must be positive.
+func NewLog(size int) *Log {
+	return &Log{records: make([]Record, size)}
+}
+
+// Add appends a record, evicting the oldest if the log is full.
+func (l *Log) Add(r Record) {
+	// The caller's slices may be shared with the PVC status it goes on to build
+	r.TriggeredBy = slices.Clone(r.TriggeredBy)
+	r.Checks = slices.Clone(r.Checks)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records[l.next] = r
+	l.next = (l.next + 1) % len(l.records)
+	if l.next == 0 {
+		l.full = true
+	}
+	l.version++
+}
+
+// List returns the records matching f, newest first.
+func (l *Log) List(f Filter) []Record {
+	records, _ := l.snapshot(f)
+	return records
+}
+
+// snapshot returns the records matching f, newest first, and the version
+// they were read at.
+func (l *Log) snapshot(f Filter) ([]Record, uint64) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	n := l.next
+	if l.full {
+		n = len(l.records)
+	}
+	out := []Record{}
+	for i := 1; i <= n; i++ {
+		r := &l.records[(l.next-i+len(l.records))%len(l.records)]
+		if !f.matches(r) {
+			continue
+		}
+		out = append(out, *r)
+		if f.Limit > 0 && len(out) == f.Limit {
+			break
+		}
+	}
+	return out, l.version
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func record(ns, pvc, action string) Record {
+	return Record{Namespace: ns, PVC: pvc, VolumeAutoscaler: pvc, Action: action}
+}
+
+func pvcNames(records []Record) []string {
+	names := make([]string, 0, len(records))
+	for _, r := range records {
+		names = append(names, r.PVC)
+	}
+	return names
+}
+
+func TestLog_EvictsOldestAndListsNewestFirst(t *testing.T) {
+	l := NewLog(3)
+	for i := range 5 {
+		l.Add(record("monitoring", fmt.Sprintf("pvc-%d", i), ActionNone))
+	}
+	got := pvcNames(l.List(Filter{}))
+	want := []string{"pvc-4", "pvc-3", "pvc-2"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("List() = %v, want %v", got, want)
+	}
+}
+
+func TestLog_Filter(t *testing.T) {
+	l := NewLog(10)
+	l.Add(record("monitoring", "loki-0", ActionNone))
+	l.Add(record("monitoring", "loki-0", ActionSkipped))
+	l.Add(record("gitlab", "loki-0", ActionExpanded))
+	l.Add(record("monitoring", "prom-0", ActionExpanded))
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"all", Filter{}, 4},
+		{"namespace", Filter{Namespace: "monitoring"}, 3},
+		{"namespace and pvc", Filter{Namespace: "monitoring", PVC: "loki-0"}, 2},
+		{"action", Filter{Action: ActionExpanded}, 2},
+		{"limit", Filter{Namespace: "monitoring", Limit: 1}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.List(tt.filter); len(got) != tt.want {
+				t.Errorf("List(%+v) returned %d records, want %d", tt.filter, len(got), tt.want)
+			}
+		})
+	}
+	if got := l.List(Filter{Namespace: "monitoring", Limit: 1}); got[0].PVC != "prom-0" {
+		t.Errorf("newest monitoring record = %s, want prom-0", got[0].PVC)
+	}
+}
+
+func TestRecord_AddCheck(t *testing.T) {
+	var r Record
+	r.AddCheck("safety", nil)
+	r.AddCheck("backendCapacity", errors.New("no room"))
+	want := []Check{{Name: "safety", Passed: true}, {Name: "backendCapacity", Reason: "no room"}}
+	if fmt.Sprint(r.Checks) != fmt.Sprint(want) {
+		t.Fatalf("Checks = %+v, want %+v", r.Checks, want)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	l := NewLog(10)
+	l.Add(record("monitoring", "loki-0", ActionSkipped))
+	l.Add(record("gitlab", "repo-0", ActionExpanded))
+	server := httptest.NewServer(l.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/debug/decisions?namespace=monitoring&pvc=loki-0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var got []Record
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Action != ActionSkipped {
+		t.Fatalf("got %+v, want the single loki-0 record", got)
+	}
+
+	resp, err = http.Get(server.URL + "/debug/decisions?limit=-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("negative limit returned %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestConfigMapWriter(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	l := NewLog(10)
+	key := types.NamespacedName{Namespace: "storage-autoscaler", Name: "decisions"}
+	w := &ConfigMapWriter{Client: c, APIReader: c, Log: l, Key: key}
+
+	readBack := func() ([]Record, string) {
+		t.Helper()
+		var cm corev1.ConfigMap
+		if err := c.Get(ctx, key, &cm); err != nil {
+			t.Fatal(err)
+		}
+		var records []Record
+		if err := json.Unmarshal([]byte(cm.Data[ConfigMapKey]), &records); err != nil {
+			t.Fatal(err)
+		}
+		return records, cm.ResourceVersion
+	}
+
+	l.Add(record("monitoring", "loki-0", ActionNone))
+	if err := w.Write(ctx); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	records, rv := readBack()
+	if len(records) != 1 {
+		t.Fatalf("got %d records after create, want 1", len(records))
+	}
+
+	// Nothing new: no write
+	if err := w.Write(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, again := readBack(); again != rv {
+		t.Errorf("unchanged log was rewritten")
+	}
+
+	l.Add(record("monitoring", "loki-0", ActionExpanded))
+	if err := w.Write(ctx); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if records, _ := readBack(); len(records) != 2 || records[0].Action != ActionExpanded {
+		t.Errorf("got %+v after update, want 2 records with the expansion first", records)
+	}
+}
+
+func TestEncodeWithin_KeepsNewest(t *testing.T) {
+	var records []Record
+	for i := range 100 {
+		r := record("monitoring", fmt.Sprintf("pvc-%d", i), ActionNone)
+		r.Time = time.Unix(int64(i), 0)
+		records = append(records, r)
+	}
+	full, err := encodeWithin(records, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := encodeWithin(records, len(full)/3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) > len(full)/3 {
+		t.Fatalf("encoded %d bytes, want at most %d", len(data), len(full)/3)
+	}
+	var got []Record
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 || got[0].PVC != "pvc-0" {
+		t.Errorf("kept %v, want a prefix of the input", pvcNames(got))
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReasonVolumeConditionAbnormal is the event reason the CSI
+// external-health-monitor uses when a driver's VolumeCondition is abnormal.
+const ReasonVolumeConditionAbnormal = "VolumeConditionAbnormal"
+
+// ConditionEvents checks the PVC and its PV for VolumeConditionAbnormal
+// events. The external-health-monitor publishes the CSI controller's
+// VolumeCondition only as events and repeats them on every check while the
+// condition lasts, so an event seen within Window means the volume is still
+// abnormal. Events are listed with field selectors, so c must read from the
+// API server rather than a cache.
+type ConditionEvents struct {
+	Window time.Duration
+	// Now returns the current time. Nil uses time.Now.
+	Now func() time.Time
+}
+
+// Name implements Checker.
+func (ConditionEvents) Name() string { return "VolumeCondition" }
+
+// Check implements Checker.
+func (e ConditionEvents) Check(ctx context.Context, c client.Reader, pvc *corev1.PersistentVolumeClaim) error {
+	now := time.Now
+	if e.Now != nil {
+		now = e.Now
+	}
+	since := now().Add(-e.Window)
+
+	if ev, err := latestAbnormal(ctx, c, pvc.Namespace, "PersistentVolumeClaim", pvc.Name, since); err != nil || ev != nil {
+		return abnormal(e.Name(), ev, err)
+	}
+	if pvc.Spec.VolumeName == "" {
+		return nil
+	}
+	// Events about cluster-scoped objects land in the default namespace
+	ev, err := latestAbnormal(ctx, c, metav1.NamespaceDefault, "PersistentVolume", pvc.Spec.VolumeName, since)
+	return abnormal(e.Name(), ev, err)
+}
+
+func abnormal(source string, ev *corev1.Event, err error) error {
+	if err != nil || ev == nil {
+		return err
+	}
+	return &UnhealthyError{Source: source, Reason: ReasonVolumeConditionAbnormal, Message: ev.Message}
+}
+
+// latestAbnormal returns the most recent VolumeConditionAbnormal event about
+// the named object seen after since, or nil.
+func latestAbnormal(ctx context.Context, c client.Reader, namespace, kind, name string, since time.Time) (*corev1.Event, error) {
+	var events corev1.EventList
+	if err := c.List(ctx, &events, client.InNamespace(namespace), client.MatchingFields{
+		"involvedObject.kind": kind,
+		"involvedObject.name": name,
+		"reason":              ReasonVolumeConditionAbnormal,
+	}); err != nil {
+		return nil, fmt.Errorf("listing events of %s %s: %w", kind, name, err)
+	}
+	var latest *corev1.Event
+	for i := range events.Items {
+		ev := &events.Items[i]
+		if seen := lastSeen(ev); seen.After(since) && (latest == nil || seen.After(lastSeen(latest))) {
+			latest = ev
+		}
+	}
+	return latest, nil
+}
+
+// lastSeen returns when an event was last observed, whichever of the core
+// and events.k8s.io fields the emitter filled in.
+func lastSeen(ev *corev1.Event) time.Time {
+	switch {
+	case ev.Series != nil:
+		return ev.Series.LastObservedTime.Time
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	}
+	return ev.CreationTimestamp.Time
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health decides whether a PVC's volume is healthy enough to be
+// expanded. Growing a volume whose backend is degraded adds load where it
+// can least be afforded, so an unhealthy volume blocks expansion.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/volume-autoscaler/volume-autoscaler/internal/config"
+)
+
+// Checker reports whether a PVC's volume is healthy.
+type Checker interface {
+	// Name identifies the health source in messages.
+	Name() string
+
+	// Check returns an *UnhealthyError when the source reports the volume
+	// as unhealthy, and nil when it reports it healthy or has nothing to
+	// say about it. Other errors mean the source could not be queried.
+	Check(ctx context.Context, c client.Reader, pvc *corev1.PersistentVolumeClaim) error
+}
+
+// UnhealthyError reports a volume a health source considers unhealthy.
+type UnhealthyError struct {
+	// Source is the name of the checker that reported the volume.
+	Source string
+	// Reason is a short CamelCase cause, e.g. VolumeConditionAbnormal or Degraded.
+	Reason string
+	// Message is the detail given by the source, if any.
+	Message string
+}
+
+func (e *UnhealthyError) Error() string {
+	msg := fmt.Sprintf("%s reports the volume unhealthy (%s)", e.Source, e.Reason)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// FromConfig returns the checkers enabled in cfg that need no Prometheus,
+// in the order they run.
+func FromConfig(cfg config.VolumeHealth) []Checker {
+	var checkers []Checker
+	if cfg.ConditionEvents {
+		window := 10 * time.Minute
+		if cfg.EventWindow != nil {
+			window = cfg.EventWindow.Duration
+		}
+		checkers = append(checkers, ConditionEvents{Window: window})
+	}
+	if cfg.LonghornNamespace != "" {
+		checkers = append(checkers, Longhorn{Namespace: cfg.LonghornNamespace})
+	}
+	return checkers
+}
+
+// Check runs every checker and returns the first *UnhealthyError. If no
+// source reports the volume unhealthy, it returns the errors of the sources
+// that could not be queried, joined, or nil.
+func Check(ctx context.Context, c client.Reader, checkers []Checker, pvc *corev1.PersistentVolumeClaim) error {
+	var failed []error
+	for _, checker := range checkers {
+		err := checker.Check(ctx, c, pvc)
+		var unhealthy *UnhealthyError
+		if errors.As(err, &unhealthy) {
+			return err
+		}
+		if err != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", checker.Name(), err))
+		}
+	}
+	return errors.Join(failed...)
+}
+
+// boundVolume returns the PV bound to pvc, or nil if it is not bound.
+func boundVolume(ctx context.Context, c client.Reader, pvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolume, error) {
+	if pvc.Spec.VolumeName == "" {
+		return nil, nil
+	}
+	var pv corev1.PersistentVolume
+	if err := c.Get(ctx, types.NamespacedName{Name: pvc.Spec.VolumeName}, &pv); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return &pv, nil
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	promclient "github.com/volume-autoscaler/volume-autoscaler/internal/prometheus"
+)
+
+func newPVC(volume string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "monitoring"},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: volume},
+	}
+}
+
+func TestKubelet(t *testing.T) {
+	tests := map[string]struct {
+		result    string
+		status    int
+		unhealthy bool
+		wantErr   bool
+	}{
+		"healthy":   {result: `[{"metric": {"persistentvolumeclaim": "data"}, "value": [1, "0"]}]`},
+		"abnormal":  {result: `[{"metric": {"persistentvolumeclaim": "data"}, "value": [1, "1"]}]`, unhealthy: true},
+		"no series": {result: `[]`},
+		"error":     {status: http.StatusInternalServerError, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				_, _ = fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":%s}}`, tt.result)
+			}))
+			defer server.Close()
+
+			err := Kubelet{Prometheus: promclient.NewClient(server.URL)}.Check(t.Context(), nil, newPVC(""))
+			var unhealthy *UnhealthyError
+			if got := errors.As(err, &unhealthy); got != tt.unhealthy {
+				t.Fatalf("unhealthy = %v, want %v (err: %v)", got, tt.unhealthy, err)
+			}
+			if got := err != nil && !tt.unhealthy; got != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func event(namespace, kind, name string, seen time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: fmt.Sprintf("%s.%d", name, seen.Unix()), Namespace: namespace},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: name, Namespace: namespace},
+		Reason:         ReasonVolumeConditionAbnormal,
+		Message:        "Volume is read-only",
+		LastTimestamp:  metav1.NewTime(seen),
+	}
+}
+
+// eventClient builds a fake client that can serve the field selectors
+// ConditionEvents lists with.
+func eventClient(objs ...client.Object) client.Client {
+	b := fake.NewClientBuilder().WithObjects(objs...)
+	b = b.WithIndex(&corev1.Event{}, "involvedObject.kind", func(o client.Object) []string {
+		return []string{o.(*corev1.Event).InvolvedObject.Kind}
+	})
+	b = b.WithIndex(&corev1.Event{}, "involvedObject.name", func(o client.Object) []string {
+		return []string{o.(*corev1.Event).InvolvedObject.Name}
+	})
+	b = b.WithIndex(&corev1.Event{}, "reason", func(o client.Object) []string {
+		return []string{o.(*corev1.Event).Reason}
+	})
+	return b.Build()
+}
+
+func TestConditionEvents(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		objects   []client.Object
+		unhealthy bool
+	}{
+		"no events": {},
+		"recent PVC event": {
+			objects:   []client.Object{event("monitoring", "PersistentVolumeClaim", "data", now.Add(-time.Minute))},
+			unhealthy: true,
+		},
+		"stale PVC event": {
+			objects: []client.Object{event("monitoring", "PersistentVolumeClaim", "data", now.Add(-time.Hour))},
+		},
+		"recent PV event": {
+			objects:   []client.Object{event(metav1.NamespaceDefault, "PersistentVolume", "pv-1", now.Add(-time.Minute))},
+			unhealthy: true,
+		},
+		"other PVC": {
+			objects: []client.Object{event("monitoring", "PersistentVolumeClaim", "logs", now.Add(-time.Minute))},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			checker := ConditionEvents{Window: 10 * time.Minute, Now: func() time.Time { return now }}
+			err := checker.Check(t.Context(), eventClient(tt.objects...), newPVC("pv-1"))
+			var unhealthy *UnhealthyError
+			if got := errors.As(err, &unhealthy); got != tt.unhealthy {
+				t.Fatalf("unhealthy = %v, want %v (err: %v)", got, tt.unhealthy, err)
+			}
+			if !tt.unhealthy && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestLonghorn(t *testing.T) {
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-123"},
+		Spec: corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{
+			CSI: &corev1.CSIPersistentVolumeSource{Driver: longhornDriver, VolumeHandle: "pvc-123"},
+		}},
+	}
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	scheme.AddKnownTypeWithName(longhornVolumeGVK, &unstructured.Unstructured{})
+
+	for robustness, wantReason := range map[string]string{
+		"healthy":  "",
+		"unknown":  "",
+		"degraded": "Degraded",
+		"faulted":  "Faulted",
+	} {
+		t.Run(robustness, func(t *testing.T) {
+			volume := &unstructured.Unstructured{Object: map[string]any{
+				"status": map[string]any{"robustness": robustness},
+			}}
+			volume.SetGroupVersionKind(longhornVolumeGVK)
+			volume.SetNamespace("longhorn-system")
+			volume.SetName("pvc-123")
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pv, volume).Build()
+
+			err := Longhorn{Namespace: "longhorn-system"}.Check(t.Context(), c, newPVC("pvc-123"))
+			var unhealthy *UnhealthyError
+			switch {
+			case wantReason == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case wantReason != "" && (!errors.As(err, &unhealthy) || unhealthy.Reason != wantReason):
+				t.Fatalf("err = %v, want an UnhealthyError with reason %s", err, wantReason)
+			}
+		})
+	}
+}
+
+func TestLonghornIgnoresOtherDrivers(t *testing.T) {
+	if err := (Longhorn{Namespace: "longhorn-system"}).Check(t.Context(), fake.NewClientBuilder().Build(), newPVC("")); err != nil {
+		t.Fatalf("unexpected error for an unbound PVC: %v", err)
+	}
+}
+
+type stubChecker struct {
+	name string
+	err  error
+}
+
+func (s stubChecker) Name() string { return s.name }
+
+func (s stubChecker) Check(context.Context, client.Reader, *corev1.PersistentVolumeClaim) error {
+	return s.err
+}
+
+func TestCheck(t *testing.T) {
+	down := stubChecker{name: "kubelet", err: errors.New("prometheus down")}
+	degraded := stubChecker{name: "Longhorn", err: &UnhealthyError{Source: "Longhorn", Reason: "Degraded"}}
+
+	// A source that cannot be queried does not hide one that reports a problem
+	err := Check(t.Context(), nil, []Checker{down, degraded}, newPVC(""))
+	var unhealthy *UnhealthyError
+	if !errors.As(err, &unhealthy) || unhealthy.Reason != "Degraded" {
+		t.Fatalf("err = %v, want the Degraded report", err)
+	}
+
+	err = Check(t.Context(), nil, []Checker{down, stubChecker{name: "VolumeCondition"}}, newPVC(""))
+	if err == nil || errors.As(err, &unhealthy) {
+		t.Fatalf("err = %v, want the query error alone", err)
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	promclient "github.com/volume-autoscaler/volume-autoscaler/internal/prometheus"
+)
+
+// Kubelet checks kubelet_volume_stats_health_abnormal, which the kubelet
+// derives from the CSI node plugin's VolumeCondition. Drivers that do not
+// report a condition export no series, which counts as healthy.
+type Kubelet struct {
+	Prometheus *promclient.Client
+}
+
+// Name implements Checker.
+func (Kubelet) Name() string { return "kubelet" }
+
+// Check implements Checker.
+func (k Kubelet) Check(ctx context.Context, _ client.Reader, pvc *corev1.PersistentVolumeClaim) error {
+	query := fmt.Sprintf(
+		`kubelet_volume_stats_health_abnormal{namespace="%s",persistentvolumeclaim="%s"}`,
+		pvc.Namespace, pvc.Name,
+	)
+	// QueryMulti rather than Query: no series is an answer, not an error
+	values, err := k.Prometheus.QueryMulti(ctx, query, "persistentvolumeclaim")
+	if err != nil {
+		return err
+	}
+	if values[pvc.Name] > 0 {
+		return &UnhealthyError{Source: k.Name(), Reason: "HealthAbnormal",
+			Message: "kubelet_volume_stats_health_abnormal is set"}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// longhornDriver is the CSI driver name of Longhorn volumes.
+const longhornDriver = "driver.longhorn.io"
+
+var longhornVolumeGVK = schema.GroupVersionKind{Group: "longhorn.io", Version: "v1beta2", Kind: "Volume"}
+
+// Longhorn checks the robustness of Longhorn volumes. A degraded volume is
+// already short of replicas and rebuilding; growing it makes every rebuild
+// copy more data, and a faulted one cannot be expanded at all. The Volume
+// CR is read as an unstructured object, so the CRD need not be installed for
+// PVCs of other drivers.
+type Longhorn struct {
+	// Namespace Longhorn is installed in, usually longhorn-system.
+	Namespace string
+}
+
+// Name implements Checker.
+func (Longhorn) Name() string { return "Longhorn" }
+
+// Check implements Checker.
+func (l Longhorn) Check(ctx context.Context, c client.Reader, pvc *corev1.PersistentVolumeClaim) error {
+	pv, err := boundVolume(ctx, c, pvc)
+	if err != nil {
+		return fmt.Errorf("getting PV %s: %w", pvc.Spec.VolumeName, err)
+	}
+	if pv == nil || pv.Spec.CSI == nil || pv.Spec.CSI.Driver != longhornDriver {
+		return nil
+	}
+	name := pv.Spec.CSI.VolumeHandle
+
+	volume := &unstructured.Unstructured{}
+	volume.SetGroupVersionKind(longhornVolumeGVK)
+	if err := c.Get(ctx, types.NamespacedName{Namespace: l.Namespace, Name: name}, volume); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("getting Longhorn volume %s: %w", name, err)
+	}
+	robustness, _, _ := unstructured.NestedString(volume.Object, "status", "robustness")
+	switch robustness {
+	case "degraded":
+		return &UnhealthyError{Source: l.Name(), Reason: "Degraded",
+			Message: fmt.Sprintf("volume %s is running with fewer healthy replicas than configured", name)}
+	case "faulted":
+		return &UnhealthyError{Source: l.Name(), Reason: "Faulted",
+			Message: fmt.Sprintf("volume %s has no healthy replica", name)}
+	}
+	return nil
+}
@@ -21,14 +21,22 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
+// Values of the mode label on ScaleEventsTotal.
+const (
+	ScaleModeNormal    = "normal"
+	ScaleModeEmergency = "emergency"
+	ScaleModeForced    = "forced"
+)
+
 var (
 	// ScaleEventsTotal tracks the total number of PVC expansions performed.
+	// The mode label distinguishes regular growth from emergency and forced expansions.
 	ScaleEventsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "volume_autoscaler_scale_events_total",
 			Help: "Total number of PVC expansion events",
 		},
-		[]string{"namespace", "pvc", "volumeautoscaler"},
+		[]string{"namespace", "pvc", "volumeautoscaler", "mode"},
 	)
 
 	// PVCUsagePercent reports the current usage percentage of each managed PVC.
@@ -40,6 +48,47 @@ var (
 		[]string{"namespace", "pvc", "volumeautoscaler"},
 	)
 
+	// PVCThresholdPercent is the effective expansion threshold of a managed PVC.
+	PVCThresholdPercent = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "volume_autoscaler_pvc_threshold_percent",
+			Help: "Usage percentage at which a managed PVC is expanded",
+		},
+		[]string{"namespace", "pvc", "volumeautoscaler"},
+	)
+
+	// PVCSizeBytes is the capacity in a managed PVC's status, as compared
+	// against maxSize. kubelet_volume_stats_capacity_bytes reports the
+	// slightly smaller filesystem size.
+	PVCSizeBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "volume_autoscaler_pvc_size_bytes",
+			Help: "Provisioned capacity of a managed PVC",
+		},
+		[]string{"namespace", "pvc", "volumeautoscaler"},
+	)
+
+	// PVCMaxSizeBytes is the effective maxSize of a managed PVC, including a
+	// per-PVC override.
+	PVCMaxSizeBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "volume_autoscaler_pvc_max_size_bytes",
+			Help: "Size a managed PVC will not be expanded beyond",
+		},
+		[]string{"namespace", "pvc", "volumeautoscaler"},
+	)
+
+	// PVCResizePendingSeconds is how long ago the last expansion of a PVC was
+	// requested while its capacity has not reached the new size yet; 0 once
+	// the resize has completed.
+	PVCResizePendingSeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "volume_autoscaler_pvc_resize_pending_seconds",
+			Help: "Seconds since the last expansion of a managed PVC that has not completed yet",
+		},
+		[]string{"namespace", "pvc", "volumeautoscaler"},
+	)
+
 	// PollErrorsTotal tracks failures during metrics polling.
 	PollErrorsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -49,6 +98,75 @@ var (
 		[]string{"namespace", "volumeautoscaler", "reason"},
 	)
 
+	// AnomaliesTotal counts abnormal growth detections by check.
+	AnomaliesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "volume_autoscaler_anomalies_total",
+			Help: "Total number of abnormal volume growth detections",
+		},
+		[]string{"namespace", "pvc", "volumeautoscaler", "kind"},
+	)
+
+	// PVCAnomalous is 1 while a PVC has an unacknowledged anomaly.
+	PVCAnomalous = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "volume_autoscaler_pvc_anomalous",
+			Help: "Whether a managed PVC has an unacknowledged growth anomaly",
+		},
+		[]string{"namespace", "pvc", "volumeautoscaler"},
+	)
+
+	// BackendCapacityInsufficient is 1 while a PVC's expansion is refused
+	// because the storage backend lacks room for it.
+	BackendCapacityInsufficient = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "volume_autoscaler_backend_capacity_insufficient",
+			Help: "Whether a managed PVC's expansion is blocked by insufficient backend capacity",
+		},
+		[]string{"namespace", "pvc", "volumeautoscaler"},
+	)
+
+	// PVCUnhealthy is 1 while a PVC's expansion is refused because a health
+	// source reports its volume unhealthy.
+	PVCUnhealthy = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "volume_autoscaler_pvc_unhealthy",
+			Help: "Whether a managed PVC's expansion is blocked because its volume is unhealthy",
+		},
+		[]string{"namespace", "pvc", "volumeautoscaler"},
+	)
+
+	// ResizeQueueDepth reports how many PVC expansions wait for a slot under
+	// a resize limit.
+	ResizeQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "volume_autoscaler_resize_queue_depth",
+			Help: "Number of PVC expansions queued by a StorageClass or CSI driver resize limit",
+		},
+		[]string{"group"},
+	)
+
+	// ProvisionedCostMonthly reports the monthly cost of the current capacity
+	// of a VolumeAutoscaler's PVCs, in the currency of the controller config.
+	// Sum by namespace for per-team chargeback.
+	ProvisionedCostMonthly = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "volume_autoscaler_provisioned_cost_monthly",
+			Help: "Monthly cost of the capacity currently provisioned for managed PVCs",
+		},
+		[]string{"namespace", "volumeautoscaler"},
+	)
+
+	// AutoscaledCostMonthly30d reports the monthly cost of the capacity added
+	// by autoscaling in the last 30 days.
+	AutoscaledCostMonthly30d = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "volume_autoscaler_autoscaled_cost_monthly_30d",
+			Help: "Monthly cost of the capacity added by autoscaling in the last 30 days",
+		},
+		[]string{"namespace", "volumeautoscaler"},
+	)
+
 	// ReconcileDurationSeconds measures reconcile loop performance.
 	ReconcileDurationSeconds = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
@@ -63,7 +181,18 @@ func init() {
 	metrics.Registry.MustRegister(
 		ScaleEventsTotal,
 		PVCUsagePercent,
+		PVCThresholdPercent,
+		PVCSizeBytes,
+		PVCMaxSizeBytes,
+		PVCResizePendingSeconds,
 		PollErrorsTotal,
+		AnomaliesTotal,
+		PVCAnomalous,
+		BackendCapacityInsufficient,
+		PVCUnhealthy,
+		ResizeQueueDepth,
+		ProvisionedCostMonthly,
+		AutoscaledCostMonthly30d,
 		ReconcileDurationSeconds,
 	)
 }
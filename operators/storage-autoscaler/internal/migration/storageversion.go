@@ -0,0 +1,119 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migration moves stored VolumeAutoscalers to the current storage
+// version, so older API versions can eventually stop being served.
+package migration
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+)
+
+// CRDName is the name of the VolumeAutoscaler CustomResourceDefinition.
+const CRDName = "volumeautoscalers.autoscaling.volume-autoscaler.io"
+
+// StorageVersionMigrator rewrites every VolumeAutoscaler in the storage
+// version and then records that version as the only one in the CRD's
+// status.storedVersions. It is the in-process equivalent of
+// kube-storage-version-migrator for this one resource.
+type StorageVersionMigrator struct {
+	// Client writes the objects and the CRD status.
+	Client client.Client
+	// APIReader reads the CRD and lists objects without starting informers.
+	APIReader client.Reader
+	// Retry is the delay between attempts while the API server or the
+	// conversion webhook is not ready. Defaults to 30s.
+	Retry time.Duration
+}
+
+// Start migrates once, retrying until it succeeds or ctx is done. It
+// implements manager.Runnable.
+func (m *StorageVersionMigrator) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("storage-version-migration")
+	retry := m.Retry
+	if retry == 0 {
+		retry = 30 * time.Second
+	}
+	_ = wait.PollUntilContextCancel(ctx, retry, true, func(ctx context.Context) (bool, error) {
+		if err := m.Migrate(ctx); err != nil {
+			log.Error(err, "storage version migration failed, will retry", "after", retry)
+			return false, nil
+		}
+		return true, nil
+	})
+	return nil
+}
+
+// NeedLeaderElection makes only the leader migrate.
+func (m *StorageVersionMigrator) NeedLeaderElection() bool {
+	return true
+}
+
+// Migrate rewrites the stored VolumeAutoscalers if the CRD still lists a
+// version other than the storage version in status.storedVersions.
+func (m *StorageVersionMigrator) Migrate(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("storage-version-migration")
+	storageVersion := autoscalingv1beta1.GroupVersion.Version
+
+	var crd apiextensionsv1.CustomResourceDefinition
+	if err := m.APIReader.Get(ctx, client.ObjectKey{Name: CRDName}, &crd); err != nil {
+		return fmt.Errorf("getting CRD %s: %w", CRDName, err)
+	}
+	stored := crd.Status.StoredVersions
+	if slices.Equal(stored, []string{storageVersion}) {
+		return nil
+	}
+	if !slices.ContainsFunc(crd.Spec.Versions, func(v apiextensionsv1.CustomResourceDefinitionVersion) bool {
+		return v.Name == storageVersion && v.Storage
+	}) {
+		return fmt.Errorf("CRD %s does not store %s yet", CRDName, storageVersion)
+	}
+
+	var list autoscalingv1beta1.VolumeAutoscalerList
+	if err := m.APIReader.List(ctx, &list); err != nil {
+		return fmt.Errorf("listing VolumeAutoscalers: %w", err)
+	}
+	// An unchanged update still re-encodes the object in the storage version
+	for i := range list.Items {
+		va := &list.Items[i]
+		if err := m.Client.Update(ctx, va); err != nil {
+			// Someone else wrote it in the meantime, which migrated it too
+			if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("rewriting VolumeAutoscaler %s/%s: %w", va.Namespace, va.Name, err)
+		}
+	}
+
+	crd.Status.StoredVersions = []string{storageVersion}
+	if err := m.Client.Status().Update(ctx, &crd); err != nil {
+		return fmt.Errorf("updating storedVersions of CRD %s: %w", CRDName, err)
+	}
+	log.Info("migrated VolumeAutoscalers to the storage version",
+		"version", storageVersion, "objects", len(list.Items), "previouslyStored", stored)
+	return nil
+}
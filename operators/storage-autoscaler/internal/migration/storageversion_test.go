@@ -0,0 +1,123 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+)
+
+func newCRD(stored ...string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: CRDName},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1beta1", Served: true, Storage: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: stored},
+	}
+}
+
+func newVA(name string) *autoscalingv1beta1.VolumeAutoscaler {
+	return &autoscalingv1beta1.VolumeAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "monitoring"},
+		Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+			Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("10Gi")},
+		},
+	}
+}
+
+func newMigrator(t *testing.T, objs ...client.Object) (*StorageVersionMigrator, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := apiextensionsv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := autoscalingv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&apiextensionsv1.CustomResourceDefinition{}).Build()
+	return &StorageVersionMigrator{Client: c, APIReader: c}, c
+}
+
+func resourceVersion(t *testing.T, c client.Client, name string) string {
+	t.Helper()
+	var va autoscalingv1beta1.VolumeAutoscaler
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "monitoring", Name: name}, &va); err != nil {
+		t.Fatal(err)
+	}
+	return va.ResourceVersion
+}
+
+func TestMigrate_RewritesObjectsAndTrimsStoredVersions(t *testing.T) {
+	m, c := newMigrator(t, newCRD("v1alpha1", "v1beta1"), newVA("loki"), newVA("grafana"))
+	before := resourceVersion(t, c, "loki")
+
+	if err := m.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	if resourceVersion(t, c, "loki") == before {
+		t.Error("VolumeAutoscaler was not rewritten")
+	}
+	var crd apiextensionsv1.CustomResourceDefinition
+	if err := c.Get(context.Background(), client.ObjectKey{Name: CRDName}, &crd); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(crd.Status.StoredVersions, []string{"v1beta1"}) {
+		t.Errorf("storedVersions = %v, want [v1beta1]", crd.Status.StoredVersions)
+	}
+}
+
+func TestMigrate_SkipsWhenAlreadyMigrated(t *testing.T) {
+	m, c := newMigrator(t, newCRD("v1beta1"), newVA("loki"))
+	before := resourceVersion(t, c, "loki")
+
+	if err := m.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if resourceVersion(t, c, "loki") != before {
+		t.Error("VolumeAutoscaler was rewritten although storedVersions was already current")
+	}
+}
+
+func TestMigrate_WaitsForStorageVersion(t *testing.T) {
+	crd := newCRD("v1alpha1")
+	crd.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1alpha1", Served: true, Storage: true}}
+	m, c := newMigrator(t, crd, newVA("loki"))
+	before := resourceVersion(t, c, "loki")
+
+	if err := m.Migrate(context.Background()); err == nil {
+		t.Fatal("expected an error while the CRD does not store v1beta1")
+	}
+	if resourceVersion(t, c, "loki") != before {
+		t.Error("VolumeAutoscaler was rewritten before the CRD was upgraded")
+	}
+}
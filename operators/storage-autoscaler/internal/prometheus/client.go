@@ -25,8 +25,16 @@ import (
 	"net/url"
 	"strconv"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/volume-autoscaler/volume-autoscaler/internal/tracing"
 )
 
+var tracer = otel.Tracer("github.com/volume-autoscaler/volume-autoscaler/internal/prometheus")
+
 // Client queries a Prometheus HTTP API for instant metrics.
 type Client struct {
 	baseURL    string
@@ -97,7 +105,38 @@ func (c *Client) QueryMulti(ctx context.Context, promql string, labelName string
 	return out, nil
 }
 
-func (c *Client) queryRaw(ctx context.Context, promql string) ([]promResult, error) {
+// Sample is one series of an instant vector result.
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// QueryVector executes a PromQL instant query and returns every series in
+// the result with its labels. An empty result is not an error.
+func (c *Client) QueryVector(ctx context.Context, promql string) ([]Sample, error) {
+	results, err := c.queryRaw(ctx, promql)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Sample, 0, len(results))
+	for _, r := range results {
+		val, err := parseValue(r.Value[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse value for %v: %w", r.Metric, err)
+		}
+		out = append(out, Sample{Labels: r.Metric, Value: val})
+	}
+	return out, nil
+}
+
+func (c *Client) queryRaw(ctx context.Context, promql string) (results []promResult, err error) {
+	ctx, span := tracer.Start(ctx, "prometheus.Query", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("promql", promql), attribute.String("server.address", c.baseURL)))
+	defer func() {
+		span.SetAttributes(attribute.Int("prometheus.results", len(results)))
+		tracing.End(span, err)
+	}()
+
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid prometheus URL: %w", err)
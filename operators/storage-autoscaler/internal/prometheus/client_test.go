@@ -131,6 +131,35 @@ func TestQueryMulti_Success(t *testing.T) {
 	}
 }
 
+func TestQueryVector_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [
+					{"metric": {"cluster": "prod", "pvc": "pvc-a"}, "value": [1, "85"]},
+					{"metric": {"cluster": "dev", "pvc": "pvc-a"}, "value": [1, "40"]}
+				]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	samples, err := c.QueryVector(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(samples))
+	}
+	if samples[1].Labels["cluster"] != "dev" || samples[1].Value != 40 {
+		t.Errorf("unexpected second sample: %+v", samples[1])
+	}
+}
+
 func TestQuery_PrometheusError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,245 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ratelimit throttles PVC expansions across every VolumeAutoscaler.
+// Each VolumeAutoscaler polls independently, so after a Prometheus outage
+// dozens of PVCs may cross their thresholds in the same few seconds; patching
+// them all at once storms the CSI controller and the storage backend. A
+// Limiter hands out resize slots per StorageClass or CSI driver and queues
+// the expansions it cannot admit, fullest PVC first.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/volume-autoscaler/volume-autoscaler/internal/config"
+	appmetrics "github.com/volume-autoscaler/volume-autoscaler/internal/metrics"
+)
+
+const (
+	// InFlightTimeout is how long an admitted resize counts against
+	// MaxConcurrent when its completion is never reported.
+	InFlightTimeout = 30 * time.Minute
+
+	// concurrencyRetry is when a PVC queued behind MaxConcurrent asks again;
+	// the end of a resize cannot be predicted.
+	concurrencyRetry = 15 * time.Second
+)
+
+// Result is the outcome of asking for a resize slot.
+type Result struct {
+	// Admitted is true when the PVC may be patched now.
+	Admitted bool
+	// Position counts the queued PVCs ahead of this one.
+	Position int
+	// RetryAfter estimates when a slot frees up for the PVC.
+	RetryAfter time.Duration
+}
+
+// QueuedError reports an expansion waiting for a slot under a resize limit.
+type QueuedError struct {
+	// Group names the limit, e.g. storageClass/harvester or driver/driver.longhorn.io.
+	Group string
+	// Position counts the queued expansions ahead of this one.
+	Position int
+	// RetryAfter estimates when a slot frees up for the PVC.
+	RetryAfter time.Duration
+}
+
+func (e *QueuedError) Error() string {
+	return fmt.Sprintf("waiting for a slot under resize limit %s, %d expansions ahead", e.Group, e.Position)
+}
+
+// Limiter admits PVC expansions under the configured resize limits. It is
+// safe for concurrent use; the zero value is not, use New. Its state lives in
+// one process, so managers sharded with --shard each enforce the full limits.
+type Limiter struct {
+	mu     sync.Mutex
+	groups map[string]*group
+}
+
+type group struct {
+	limit config.ResizeLimit
+	// bucket is nil when the limit has no PerMinute.
+	bucket   *rate.Limiter
+	waiting  map[types.NamespacedName]waiter
+	inFlight map[types.NamespacedName]time.Time
+}
+
+type waiter struct {
+	priority float64
+	since    time.Time
+	expires  time.Time
+}
+
+// New returns an empty Limiter.
+func New() *Limiter {
+	return &Limiter{groups: map[string]*group{}}
+}
+
+// Admit asks for a slot to expand pvc under limit. Priority orders the
+// queue, higher first; callers pass how full the PVC is. A PVC that is not
+// admitted stays queued for ttl, so callers must ask again before it runs
+// out to keep their place. An admitted PVC counts against MaxConcurrent
+// until Done is called for it.
+func (l *Limiter) Admit(now time.Time, limit config.ResizeLimit, pvc types.NamespacedName,
+	priority float64, ttl time.Duration) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.expire(now)
+	g := l.group(now, limit)
+	w, ok := g.waiting[pvc]
+	if !ok {
+		w.since = now
+	}
+	w.priority = priority
+	w.expires = now.Add(ttl)
+	g.waiting[pvc] = w
+
+	position := 0
+	for other, o := range g.waiting {
+		if other != pvc && ahead(other, o, pvc, w) {
+			position++
+		}
+	}
+
+	slots := math.MaxInt
+	retry := time.Duration(0)
+	if g.limit.MaxConcurrent > 0 {
+		slots = max(g.limit.MaxConcurrent-len(g.inFlight), 0)
+		if position >= slots {
+			retry = concurrencyRetry
+		}
+	}
+	if g.bucket != nil {
+		tokens := g.bucket.TokensAt(now)
+		if int(tokens) < slots {
+			slots = max(int(tokens), 0)
+		}
+		if position >= int(tokens) {
+			missing := float64(position+1) - tokens
+			retry = max(retry, time.Duration(missing/float64(g.bucket.Limit())*float64(time.Second)))
+		}
+	}
+
+	res := Result{Position: position, RetryAfter: retry}
+	if position < slots && (g.bucket == nil || g.bucket.AllowN(now, 1)) {
+		delete(g.waiting, pvc)
+		g.inFlight[pvc] = now
+		res = Result{Admitted: true}
+	}
+	appmetrics.ResizeQueueDepth.WithLabelValues(limit.Group()).Set(float64(len(g.waiting)))
+	return res
+}
+
+// Forget removes pvc from the queue, e.g. because it no longer needs to
+// grow. It does not end an admitted resize.
+func (l *Limiter) Forget(pvc types.NamespacedName) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for name, g := range l.groups {
+		if _, ok := g.waiting[pvc]; ok {
+			delete(g.waiting, pvc)
+			appmetrics.ResizeQueueDepth.WithLabelValues(name).Set(float64(len(g.waiting)))
+		}
+	}
+}
+
+// Done reports that the resize admitted for pvc has finished, freeing its
+// MaxConcurrent slot.
+func (l *Limiter) Done(pvc types.NamespacedName) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, g := range l.groups {
+		delete(g.inFlight, pvc)
+	}
+}
+
+// Queued returns the number of PVCs waiting in the group of limit.
+func (l *Limiter) Queued(limit config.ResizeLimit) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if g, ok := l.groups[limit.Group()]; ok {
+		return len(g.waiting)
+	}
+	return 0
+}
+
+// group returns the state of limit's group, applying changes to the limit
+// made by a config reload.
+func (l *Limiter) group(now time.Time, limit config.ResizeLimit) *group {
+	g, ok := l.groups[limit.Group()]
+	if !ok {
+		g = &group{
+			waiting:  map[types.NamespacedName]waiter{},
+			inFlight: map[types.NamespacedName]time.Time{},
+		}
+		l.groups[limit.Group()] = g
+	}
+	if !ok || g.limit != limit {
+		g.limit = limit
+		switch {
+		case limit.PerMinute <= 0:
+			g.bucket = nil
+		case g.bucket == nil:
+			g.bucket = rate.NewLimiter(rate.Limit(limit.PerMinute/60), limit.Burst)
+		default:
+			g.bucket.SetLimitAt(now, rate.Limit(limit.PerMinute/60))
+			g.bucket.SetBurstAt(now, limit.Burst)
+		}
+	}
+	return g
+}
+
+// expire drops queued PVCs that stopped asking and resizes never reported
+// done.
+func (l *Limiter) expire(now time.Time) {
+	for name, g := range l.groups {
+		before := len(g.waiting)
+		for pvc, w := range g.waiting {
+			if now.After(w.expires) {
+				delete(g.waiting, pvc)
+			}
+		}
+		for pvc, since := range g.inFlight {
+			if now.Sub(since) > InFlightTimeout {
+				delete(g.inFlight, pvc)
+			}
+		}
+		if len(g.waiting) != before {
+			appmetrics.ResizeQueueDepth.WithLabelValues(name).Set(float64(len(g.waiting)))
+		}
+	}
+}
+
+// ahead reports whether waiter a is served before waiter b: fuller first,
+// then longest waiting, then by name so the order is total.
+func ahead(aName types.NamespacedName, a waiter, bName types.NamespacedName, b waiter) bool {
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	if !a.since.Equal(b.since) {
+		return a.since.Before(b.since)
+	}
+	return aName.String() < bName.String()
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/volume-autoscaler/volume-autoscaler/internal/config"
+)
+
+var t0 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func pvc(name string) types.NamespacedName {
+	return types.NamespacedName{Namespace: "default", Name: name}
+}
+
+func TestAdmit_Rate(t *testing.T) {
+	l := New()
+	limit := config.ResizeLimit{Driver: "driver.harvesterhci.io", PerMinute: 6, Burst: 2}
+
+	for _, name := range []string{"a", "b"} {
+		if res := l.Admit(t0, limit, pvc(name), 0.9, time.Minute); !res.Admitted {
+			t.Fatalf("%s should be admitted within the burst: %+v", name, res)
+		}
+	}
+	res := l.Admit(t0, limit, pvc("c"), 0.9, time.Minute)
+	if res.Admitted || res.Position != 0 || res.RetryAfter != 10*time.Second {
+		t.Errorf("c = %+v, want queued first with a 10s retry", res)
+	}
+	if got := l.Queued(limit); got != 1 {
+		t.Errorf("queued = %d, want 1", got)
+	}
+	if res := l.Admit(t0.Add(10*time.Second), limit, pvc("c"), 0.9, time.Minute); !res.Admitted {
+		t.Errorf("c should be admitted once a token refills: %+v", res)
+	}
+}
+
+func TestAdmit_FullestFirst(t *testing.T) {
+	l := New()
+	limit := config.ResizeLimit{StorageClass: "harvester", PerMinute: 1, Burst: 1}
+	l.Admit(t0, limit, pvc("warm"), 0.5, time.Hour) // takes the only token
+
+	l.Admit(t0, limit, pvc("low"), 0.85, time.Hour)
+	res := l.Admit(t0, limit, pvc("high"), 0.97, time.Hour)
+	if res.Admitted || res.Position != 0 {
+		t.Errorf("high = %+v, want queued at the head", res)
+	}
+
+	later := t0.Add(time.Minute)
+	if res := l.Admit(later, limit, pvc("low"), 0.85, time.Hour); res.Admitted || res.Position != 1 {
+		t.Errorf("low = %+v, want it to leave the token to high", res)
+	}
+	if res := l.Admit(later, limit, pvc("high"), 0.97, time.Hour); !res.Admitted {
+		t.Errorf("high should take the refilled token: %+v", res)
+	}
+	if res := l.Admit(later.Add(time.Minute), limit, pvc("low"), 0.85, time.Hour); !res.Admitted {
+		t.Errorf("low should be admitted next: %+v", res)
+	}
+}
+
+func TestAdmit_MaxConcurrent(t *testing.T) {
+	l := New()
+	limit := config.ResizeLimit{Driver: "driver.longhorn.io", MaxConcurrent: 1}
+
+	if res := l.Admit(t0, limit, pvc("a"), 0.9, time.Minute); !res.Admitted {
+		t.Fatalf("a should be admitted: %+v", res)
+	}
+	if res := l.Admit(t0, limit, pvc("b"), 0.9, time.Minute); res.Admitted || res.RetryAfter != concurrencyRetry {
+		t.Errorf("b = %+v, want queued behind the running resize", res)
+	}
+	l.Done(pvc("a"))
+	if res := l.Admit(t0, limit, pvc("b"), 0.9, time.Minute); !res.Admitted {
+		t.Errorf("b should be admitted once a is done: %+v", res)
+	}
+	if res := l.Admit(t0.Add(InFlightTimeout+time.Second), limit, pvc("c"), 0.9, time.Minute); !res.Admitted {
+		t.Errorf("an unreported resize should stop counting after the timeout: %+v", res)
+	}
+}
+
+func TestAdmit_ExpiryAndForget(t *testing.T) {
+	l := New()
+	limit := config.ResizeLimit{StorageClass: "harvester", PerMinute: 1, Burst: 1}
+	l.Admit(t0, limit, pvc("warm"), 0.5, time.Minute)
+	l.Admit(t0, limit, pvc("gone"), 0.99, time.Minute)
+	l.Admit(t0, limit, pvc("shrunk"), 0.98, time.Hour)
+
+	l.Forget(pvc("shrunk"))
+	res := l.Admit(t0.Add(2*time.Minute), limit, pvc("waiting"), 0.9, time.Minute)
+	if !res.Admitted {
+		t.Errorf("waiting = %+v, want admitted after the others left the queue", res)
+	}
+	if got := l.Queued(limit); got != 0 {
+		t.Errorf("queued = %d, want 0", got)
+	}
+}
+
+func TestAdmit_LimitReload(t *testing.T) {
+	l := New()
+	limit := config.ResizeLimit{StorageClass: "harvester", PerMinute: 1, Burst: 1}
+	l.Admit(t0, limit, pvc("a"), 0.9, time.Minute)
+	if res := l.Admit(t0, limit, pvc("b"), 0.9, time.Minute); res.Admitted {
+		t.Fatal("b should be queued under the first limit")
+	}
+	limit.PerMinute = 0
+	limit.MaxConcurrent = 5
+	if res := l.Admit(t0, limit, pvc("b"), 0.9, time.Minute); !res.Admitted {
+		t.Errorf("b should be admitted once the rate limit is removed: %+v", res)
+	}
+}
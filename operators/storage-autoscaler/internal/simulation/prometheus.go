@@ -0,0 +1,265 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	selectorRe = regexp.MustCompile(`^\s*([a-zA-Z_:][a-zA-Z0-9_:]*)\s*(?:\{(.*)\})?\s*$`)
+	matcherRe  = regexp.MustCompile(`([a-zA-Z_][a-zA-Z0-9_]*)\s*=\s*"([^"]*)"`)
+)
+
+// FakePrometheus is an in-process Prometheus HTTP API serving scripted
+// kubelet volume series. It implements /api/v1/query and /api/v1/query_range
+// for plain series selectors with equality matchers, which is all the
+// controller issues.
+type FakePrometheus struct {
+	server *httptest.Server
+
+	mu     sync.Mutex
+	now    time.Time
+	series map[string]*fakeSeries
+}
+
+type fakeSeries struct {
+	labels  map[string]string
+	samples []fakeSample
+}
+
+type fakeSample struct {
+	t time.Time
+	v float64
+}
+
+// NewFakePrometheus starts a fake Prometheus server. Call Close when done.
+func NewFakePrometheus() *FakePrometheus {
+	f := &FakePrometheus{
+		now:    time.Now(),
+		series: make(map[string]*fakeSeries),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/query", f.handleQuery)
+	mux.HandleFunc("/api/v1/query_range", f.handleQueryRange)
+	f.server = httptest.NewServer(mux)
+	return f
+}
+
+// URL returns the base URL to use as a VolumeAutoscaler prometheusURL.
+func (f *FakePrometheus) URL() string {
+	return f.server.URL
+}
+
+// Close shuts the server down.
+func (f *FakePrometheus) Close() {
+	f.server.Close()
+}
+
+// SetTime sets the evaluation time for instant queries without a time parameter.
+func (f *FakePrometheus) SetTime(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = t
+}
+
+// Record appends a sample for the kubelet volume series of a PVC.
+// Queries return the latest sample at or before the evaluation time.
+func (f *FakePrometheus) Record(t time.Time, metric, namespace, pvc string, value float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := metric + "/" + namespace + "/" + pvc
+	s, ok := f.series[key]
+	if !ok {
+		s = &fakeSeries{labels: map[string]string{
+			"__name__":              metric,
+			"namespace":             namespace,
+			"persistentvolumeclaim": pvc,
+		}}
+		f.series[key] = s
+	}
+	s.samples = append(s.samples, fakeSample{t: t, v: value})
+	sort.SliceStable(s.samples, func(i, j int) bool { return s.samples[i].t.Before(s.samples[j].t) })
+}
+
+func (f *FakePrometheus) handleQuery(w http.ResponseWriter, r *http.Request) {
+	matchers, err := parseSelector(r.FormValue("query"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	at := f.now
+	if ts := r.FormValue("time"); ts != "" {
+		if at, err = parseTime(ts); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	result := []map[string]any{}
+	for _, s := range f.matching(matchers) {
+		if v, ok := s.valueAt(at); ok {
+			result = append(result, map[string]any{
+				"metric": s.labels,
+				"value":  []any{float64(at.Unix()), formatValue(v)},
+			})
+		}
+	}
+	writeData(w, "vector", result)
+}
+
+func (f *FakePrometheus) handleQueryRange(w http.ResponseWriter, r *http.Request) {
+	matchers, err := parseSelector(r.FormValue("query"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	start, err := parseTime(r.FormValue("start"))
+	if err != nil {
+		writeError(w, fmt.Errorf("invalid start: %w", err))
+		return
+	}
+	end, err := parseTime(r.FormValue("end"))
+	if err != nil {
+		writeError(w, fmt.Errorf("invalid end: %w", err))
+		return
+	}
+	step, err := parseStep(r.FormValue("step"))
+	if err != nil {
+		writeError(w, fmt.Errorf("invalid step: %w", err))
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := []map[string]any{}
+	for _, s := range f.matching(matchers) {
+		values := [][]any{}
+		for t := start; !t.After(end); t = t.Add(step) {
+			if v, ok := s.valueAt(t); ok {
+				values = append(values, []any{float64(t.Unix()), formatValue(v)})
+			}
+		}
+		if len(values) > 0 {
+			result = append(result, map[string]any{"metric": s.labels, "values": values})
+		}
+	}
+	writeData(w, "matrix", result)
+}
+
+func (f *FakePrometheus) matching(matchers map[string]string) []*fakeSeries {
+	var out []*fakeSeries
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := f.series[k]
+		match := true
+		for name, want := range matchers {
+			if s.labels[name] != want {
+				match = false
+				break
+			}
+		}
+		if match {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (s *fakeSeries) valueAt(t time.Time) (float64, bool) {
+	var v float64
+	found := false
+	for _, smp := range s.samples {
+		if smp.t.After(t) {
+			break
+		}
+		v, found = smp.v, true
+	}
+	return v, found
+}
+
+// parseSelector turns `metric{a="b",c="d"}` into equality matchers.
+func parseSelector(query string) (map[string]string, error) {
+	m := selectorRe.FindStringSubmatch(query)
+	if m == nil {
+		return nil, fmt.Errorf("unsupported query: %s", query)
+	}
+	matchers := map[string]string{"__name__": m[1]}
+	for _, lm := range matcherRe.FindAllStringSubmatch(m[2], -1) {
+		matchers[lm[1]] = lm[2]
+	}
+	return matchers, nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(frac*1e9)), nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+func parseStep(s string) (time.Duration, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		if f <= 0 {
+			return 0, fmt.Errorf("step must be positive")
+		}
+		return time.Duration(f * float64(time.Second)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err == nil && d <= 0 {
+		return 0, fmt.Errorf("step must be positive")
+	}
+	return d, err
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func writeData(w http.ResponseWriter, resultType string, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status": "success",
+		"data":   map[string]any{"resultType": resultType, "result": result},
+	})
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status":    "error",
+		"errorType": "bad_data",
+		"error":     err.Error(),
+	})
+}
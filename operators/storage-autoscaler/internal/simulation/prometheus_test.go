@@ -0,0 +1,126 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	promclient "github.com/volume-autoscaler/volume-autoscaler/internal/prometheus"
+)
+
+func TestFakePrometheus_InstantQueryReturnsLatestSample(t *testing.T) {
+	f := NewFakePrometheus()
+	defer f.Close()
+
+	t0 := time.Unix(1000, 0)
+	f.Record(t0, "kubelet_volume_stats_used_bytes", "ns", "data", 10)
+	f.Record(t0.Add(time.Minute), "kubelet_volume_stats_used_bytes", "ns", "data", 20)
+	f.Record(t0, "kubelet_volume_stats_used_bytes", "ns", "other", 99)
+
+	c := promclient.NewClient(f.URL())
+	query := `kubelet_volume_stats_used_bytes{namespace="ns",persistentvolumeclaim="data"}`
+
+	f.SetTime(t0.Add(30 * time.Second))
+	val, err := c.Query(context.Background(), query)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if val != 10 {
+		t.Errorf("expected 10, got %f", val)
+	}
+
+	f.SetTime(t0.Add(2 * time.Minute))
+	val, err = c.Query(context.Background(), query)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if val != 20 {
+		t.Errorf("expected 20, got %f", val)
+	}
+}
+
+func TestFakePrometheus_UnknownSeriesIsEmpty(t *testing.T) {
+	f := NewFakePrometheus()
+	defer f.Close()
+
+	c := promclient.NewClient(f.URL())
+	if _, err := c.Query(context.Background(), `kubelet_volume_stats_health_abnormal{namespace="ns"}`); err == nil {
+		t.Fatal("expected error for empty results")
+	}
+}
+
+func TestFakePrometheus_QueryRange(t *testing.T) {
+	f := NewFakePrometheus()
+	defer f.Close()
+
+	t0 := time.Unix(1000, 0)
+	for i := range 5 {
+		f.Record(t0.Add(time.Duration(i)*time.Minute), "kubelet_volume_stats_used_bytes", "ns", "data", float64(i))
+	}
+
+	q := url.Values{}
+	q.Set("query", `kubelet_volume_stats_used_bytes{persistentvolumeclaim="data"}`)
+	q.Set("start", "1000")
+	q.Set("end", "1240")
+	q.Set("step", "2m")
+	resp, err := http.Get(f.URL() + "/api/v1/query_range?" + q.Encode())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body struct {
+		Status string `json:"status"`
+		Data   struct {
+			ResultType string `json:"resultType"`
+			Result     []struct {
+				Values [][2]any `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if body.Status != "success" || body.Data.ResultType != "matrix" {
+		t.Fatalf("unexpected response: %+v", body)
+	}
+	if len(body.Data.Result) != 1 {
+		t.Fatalf("expected 1 series, got %d", len(body.Data.Result))
+	}
+	var got []string
+	for _, v := range body.Data.Result[0].Values {
+		got = append(got, v[1].(string))
+	}
+	if want := []string{"0", "2", "4"}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("expected values %v, got %v", want, got)
+	}
+}
+
+func TestFakePrometheus_RejectsUnsupportedQuery(t *testing.T) {
+	f := NewFakePrometheus()
+	defer f.Close()
+
+	c := promclient.NewClient(f.URL())
+	if _, err := c.Query(context.Background(), `sum(rate(foo[5m]))`); err == nil {
+		t.Fatal("expected error for unsupported query")
+	}
+}
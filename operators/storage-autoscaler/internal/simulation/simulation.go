@@ -0,0 +1,363 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulation replays scripted volume usage against the
+// VolumeAutoscaler reconciler, so a proposed spec can be validated offline
+// (typically against envtest) before it is applied to a cluster.
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/config"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/controller"
+	"github.com/volume-autoscaler/volume-autoscaler/internal/decision"
+)
+
+// simulationEpoch is the fixed start time of every run, so results are
+// reproducible.
+var simulationEpoch = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Curve returns the bytes written to the volume after elapsed simulated time.
+// Values above the current capacity are clamped, as a full volume would be.
+type Curve func(elapsed time.Duration) int64
+
+// Constant is a curve that never changes.
+func Constant(used resource.Quantity) Curve {
+	v := used.Value()
+	return func(time.Duration) int64 { return v }
+}
+
+// Linear is a curve that starts at initial and grows by perMinute.
+func Linear(initial, perMinute resource.Quantity) Curve {
+	start, rate := initial.Value(), perMinute.Value()
+	return func(elapsed time.Duration) int64 {
+		return start + int64(float64(rate)*elapsed.Minutes())
+	}
+}
+
+// Action is what the controller did with the PVC at one step.
+type Action string
+
+const (
+	// ActionNone means usage was below threshold, or nothing observable happened.
+	ActionNone Action = ""
+	// ActionExpanded is a regular expansion.
+	ActionExpanded Action = "Expanded"
+	// ActionEmergencyExpanded is an expansion past the cooldown.
+	ActionEmergencyExpanded Action = "EmergencyExpanded"
+	// ActionCooldown means usage was over threshold but the cooldown held the expansion back.
+	ActionCooldown Action = "Cooldown"
+	// ActionMaxSize means usage was over threshold but the PVC is already at maxSize.
+	ActionMaxSize Action = "MaxSizeReached"
+)
+
+// Scenario describes one simulated run for a single PVC.
+type Scenario struct {
+	// Name is used for the PVC and the VolumeAutoscaler. Defaults to "simulation".
+	Name string
+	// Namespace must already exist. Defaults to "default".
+	Namespace string
+	// StorageClassName selects an existing StorageClass. When empty, an
+	// expandable class is created for the run and removed afterwards.
+	StorageClassName string
+	// InitialSize is the PVC's starting capacity.
+	InitialSize resource.Quantity
+	// Spec is the VolumeAutoscaler spec under test. Target and prometheusURL
+	// are filled in by the harness.
+	Spec autoscalingv1beta1.VolumeAutoscalerSpec
+	// Usage scripts the bytes used over time.
+	Usage Curve
+	// Duration is the simulated time span.
+	Duration time.Duration
+	// Step is the simulated time between polls. Defaults to spec.source.pollInterval or 60s.
+	Step time.Duration
+	// Config optionally overrides the controller-wide defaults.
+	Config *config.Store
+}
+
+// Step is the observed outcome of one poll.
+type Step struct {
+	Elapsed      time.Duration
+	UsedBytes    int64
+	Capacity     resource.Quantity
+	UsagePercent int32
+	Action       Action
+	// NewSize is set for expansions.
+	NewSize *resource.Quantity
+}
+
+// Result is the full timeline of a run.
+type Result struct {
+	Steps []Step
+}
+
+// Actions returns the sequence of non-empty actions, in order.
+func (r *Result) Actions() []Action {
+	var out []Action
+	for _, s := range r.Steps {
+		if s.Action != ActionNone {
+			out = append(out, s.Action)
+		}
+	}
+	return out
+}
+
+// Expansions returns the steps at which the PVC was expanded.
+func (r *Result) Expansions() []Step {
+	var out []Step
+	for _, s := range r.Steps {
+		if s.Action == ActionExpanded || s.Action == ActionEmergencyExpanded {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// FinalSize returns the PVC capacity after the last step.
+func (r *Result) FinalSize() resource.Quantity {
+	if len(r.Steps) == 0 {
+		return resource.Quantity{}
+	}
+	last := r.Steps[len(r.Steps)-1]
+	if last.NewSize != nil {
+		return *last.NewSize
+	}
+	return last.Capacity
+}
+
+// String renders the timeline as one line per step with an action.
+func (r *Result) String() string {
+	var b strings.Builder
+	for _, s := range r.Steps {
+		if s.Action == ActionNone {
+			continue
+		}
+		fmt.Fprintf(&b, "%8s %3d%% %-8s %s", s.Elapsed, s.UsagePercent, s.Capacity.String(), s.Action)
+		if s.NewSize != nil {
+			fmt.Fprintf(&b, " -> %s", s.NewSize.String())
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Run creates the scenario's objects through c, drives the reconciler with a
+// fake clock and fake Prometheus, and removes the objects again. After each
+// expansion the PVC's status capacity is set to the new request, emulating a
+// CSI resize that completes before the next poll.
+func Run(ctx context.Context, c client.Client, sc Scenario) (*Result, error) {
+	sc.setDefaults()
+	if sc.Usage == nil {
+		return nil, fmt.Errorf("scenario %s has no usage curve", sc.Name)
+	}
+
+	prom := NewFakePrometheus()
+	defer prom.Close()
+	clk := clocktesting.NewFakePassiveClock(simulationEpoch)
+	recorder := events.NewFakeRecorder(256)
+	decisions := decision.NewLog(16)
+	r := &controller.VolumeAutoscalerReconciler{
+		Client:    c,
+		Scheme:    c.Scheme(),
+		Recorder:  recorder,
+		Config:    sc.Config,
+		Clock:     clk,
+		Decisions: decisions,
+	}
+
+	cleanup, err := sc.createObjects(ctx, c, prom.URL())
+	defer func() { cleanup(r) }()
+	if err != nil {
+		return nil, err
+	}
+
+	key := types.NamespacedName{Namespace: sc.Namespace, Name: sc.Name}
+	result := &Result{}
+	for elapsed := time.Duration(0); elapsed <= sc.Duration; elapsed += sc.Step {
+		now := simulationEpoch.Add(elapsed)
+		clk.SetTime(now)
+		prom.SetTime(now)
+
+		var pvc corev1.PersistentVolumeClaim
+		if err := c.Get(ctx, key, &pvc); err != nil {
+			return nil, fmt.Errorf("getting PVC: %w", err)
+		}
+		capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+		used := min(max(sc.Usage(elapsed), 0), capacity.Value())
+		prom.Record(now, "kubelet_volume_stats_used_bytes", sc.Namespace, sc.Name, float64(used))
+		prom.Record(now, "kubelet_volume_stats_capacity_bytes", sc.Namespace, sc.Name, float64(capacity.Value()))
+		before := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+
+		if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+			return nil, fmt.Errorf("reconcile at %s: %w", elapsed, err)
+		}
+		reasons := drainReasons(recorder)
+		decided := decisions.List(decision.Filter{Namespace: sc.Namespace, PVC: sc.Name, Limit: 1})
+
+		if err := c.Get(ctx, key, &pvc); err != nil {
+			return nil, fmt.Errorf("getting PVC: %w", err)
+		}
+		after := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+
+		step := Step{
+			Elapsed:      elapsed,
+			UsedBytes:    used,
+			Capacity:     capacity,
+			UsagePercent: int32(math.Round(float64(used) / float64(capacity.Value()) * 100)),
+		}
+		switch {
+		case after.Cmp(before) != 0:
+			step.Action = ActionExpanded
+			if reasons[string(ActionEmergencyExpanded)] {
+				step.Action = ActionEmergencyExpanded
+			}
+			newSize := after.DeepCopy()
+			step.NewSize = &newSize
+
+			pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: newSize}
+			if err := c.Status().Update(ctx, &pvc); err != nil {
+				return nil, fmt.Errorf("emulating resize: %w", err)
+			}
+		case reasons[string(ActionMaxSize)]:
+			step.Action = ActionMaxSize
+		case len(decided) > 0 && decided[0].Time.Equal(now) && cooldownHeld(decided[0]):
+			step.Action = ActionCooldown
+		}
+		result.Steps = append(result.Steps, step)
+	}
+	return result, nil
+}
+
+// cooldownHeld reports whether the cooldown check blocked the decision.
+func cooldownHeld(rec decision.Record) bool {
+	for _, check := range rec.Checks {
+		if check.Name == controller.CheckCooldown && !check.Passed {
+			return true
+		}
+	}
+	return false
+}
+
+func (sc *Scenario) setDefaults() {
+	if sc.Name == "" {
+		sc.Name = "simulation"
+	}
+	if sc.Namespace == "" {
+		sc.Namespace = "default"
+	}
+	if sc.Step == 0 {
+		sc.Step = time.Minute
+		if sc.Spec.Source.PollInterval != nil {
+			sc.Step = sc.Spec.Source.PollInterval.Duration
+		}
+	}
+}
+
+// createObjects creates the StorageClass (if needed), PVC and VolumeAutoscaler.
+// The returned cleanup is always non-nil and removes whatever was created.
+func (sc *Scenario) createObjects(ctx context.Context, c client.Client, promURL string) (func(*controller.VolumeAutoscalerReconciler), error) {
+	var created []client.Object
+	cleanup := func(r *controller.VolumeAutoscalerReconciler) {
+		for i := len(created) - 1; i >= 0; i-- {
+			obj := created[i]
+			_ = c.Delete(ctx, obj)
+			if _, ok := obj.(*autoscalingv1beta1.VolumeAutoscaler); ok {
+				// Let the finalizer run so the CR does not linger
+				_, _ = r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+			}
+		}
+	}
+
+	scName := sc.StorageClassName
+	if scName == "" {
+		scName = sc.Name + "-expandable"
+		class := &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: scName},
+			Provisioner:          "simulation.volume-autoscaler.io",
+			AllowVolumeExpansion: ptr.To(true),
+		}
+		if err := c.Create(ctx, class); err != nil {
+			return cleanup, fmt.Errorf("creating StorageClass: %w", err)
+		}
+		created = append(created, class)
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: sc.Name, Namespace: sc.Namespace},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: &scName,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: sc.InitialSize},
+			},
+		},
+	}
+	if err := c.Create(ctx, pvc); err != nil {
+		return cleanup, fmt.Errorf("creating PVC: %w", err)
+	}
+	created = append(created, pvc)
+	pvc.Status.Phase = corev1.ClaimBound
+	pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: sc.InitialSize}
+	if err := c.Status().Update(ctx, pvc); err != nil {
+		return cleanup, fmt.Errorf("setting PVC capacity: %w", err)
+	}
+
+	spec := *sc.Spec.DeepCopy()
+	spec.Target = autoscalingv1beta1.VolumeAutoscalerTarget{PVCName: sc.Name}
+	spec.Source.Prometheus.URL = promURL
+	va := &autoscalingv1beta1.VolumeAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: sc.Name, Namespace: sc.Namespace},
+		Spec:       spec,
+	}
+	if err := c.Create(ctx, va); err != nil {
+		return cleanup, fmt.Errorf("creating VolumeAutoscaler: %w", err)
+	}
+	created = append(created, va)
+	return cleanup, nil
+}
+
+// drainReasons empties the recorder and returns the event reasons seen.
+func drainReasons(recorder *events.FakeRecorder) map[string]bool {
+	reasons := make(map[string]bool)
+	for {
+		select {
+		case e := <-recorder.Events:
+			// FakeRecorder formats events as "<type> <reason> <note>"
+			if fields := strings.Fields(e); len(fields) > 1 {
+				reasons[fields[1]] = true
+			}
+		default:
+			return reasons
+		}
+	}
+}
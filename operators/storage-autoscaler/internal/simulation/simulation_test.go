@@ -0,0 +1,106 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+)
+
+var _ = Describe("Simulation", func() {
+	It("should expand, respect cooldown and stop at maxSize under steady growth", func() {
+		result, err := Run(ctx, k8sClient, Scenario{
+			Name:        "sim-steady",
+			InitialSize: resource.MustParse("10Gi"),
+			Usage:       Linear(resource.MustParse("7Gi"), resource.MustParse("500Mi")),
+			Duration:    30 * time.Minute,
+			Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+				Trigger: autoscalingv1beta1.TriggerSpec{ThresholdPercent: 80},
+				Growth: autoscalingv1beta1.GrowthSpec{
+					IncreasePercent: 20,
+					CooldownPeriod:  &metav1.Duration{Duration: 5 * time.Minute},
+				},
+				Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("20Gi")},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		GinkgoWriter.Print(result.String())
+
+		actions := result.Actions()
+		Expect(actions).NotTo(BeEmpty())
+		Expect(actions[0]).To(Equal(ActionExpanded))
+		Expect(actions).To(ContainElement(ActionCooldown))
+		Expect(actions[len(actions)-1]).To(Equal(ActionMaxSize))
+		Expect(actions).NotTo(ContainElement(ActionEmergencyExpanded))
+
+		expansions := result.Expansions()
+		for i := 1; i < len(expansions); i++ {
+			Expect(expansions[i].Elapsed - expansions[i-1].Elapsed).To(BeNumerically(">=", 5*time.Minute))
+		}
+		final := result.FinalSize()
+		Expect(final.Cmp(resource.MustParse("20Gi"))).To(Equal(0))
+	})
+
+	It("should emergency-expand during cooldown when usage crosses the emergency threshold", func() {
+		result, err := Run(ctx, k8sClient, Scenario{
+			Name:        "sim-emergency",
+			InitialSize: resource.MustParse("10Gi"),
+			Usage:       Linear(resource.MustParse("8704Mi"), resource.MustParse("1Gi")),
+			Duration:    4 * time.Minute,
+			Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+				Trigger: autoscalingv1beta1.TriggerSpec{
+					ThresholdPercent:          80,
+					EmergencyThresholdPercent: 95,
+				},
+				Growth: autoscalingv1beta1.GrowthSpec{
+					IncreasePercent:          20,
+					EmergencyIncreasePercent: 100,
+					CooldownPeriod:           &metav1.Duration{Duration: 10 * time.Minute},
+				},
+				Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("100Gi")},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		GinkgoWriter.Print(result.String())
+
+		Expect(result.Actions()).To(Equal([]Action{ActionExpanded, ActionCooldown, ActionEmergencyExpanded}))
+		final := result.FinalSize()
+		Expect(final.Cmp(resource.MustParse("24Gi"))).To(Equal(0))
+	})
+
+	It("should do nothing while usage stays below threshold", func() {
+		result, err := Run(ctx, k8sClient, Scenario{
+			Name:        "sim-idle",
+			InitialSize: resource.MustParse("10Gi"),
+			Usage:       Constant(resource.MustParse("5Gi")),
+			Duration:    time.Hour,
+			Spec: autoscalingv1beta1.VolumeAutoscalerSpec{
+				Limits: autoscalingv1beta1.LimitsSpec{MaxSize: resource.MustParse("20Gi")},
+				Source: autoscalingv1beta1.SourceSpec{PollInterval: &metav1.Duration{Duration: 5 * time.Minute}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Steps).To(HaveLen(13))
+		Expect(result.Actions()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,51 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSetup_NoEndpointIsNoop(t *testing.T) {
+	shutdown, err := Setup(context.Background(), "test", Options{SampleRatio: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
+
+func TestSetup_RejectsSampleRatioOutOfRange(t *testing.T) {
+	for _, ratio := range []float64{-0.1, 1.5} {
+		if _, err := Setup(context.Background(), "test", Options{Endpoint: "localhost:4317", SampleRatio: ratio}); err == nil {
+			t.Errorf("ratio %v: expected an error", ratio)
+		}
+	}
+}
+
+func TestSetup_InstallsProvider(t *testing.T) {
+	// the gRPC exporter connects lazily, so no collector is needed
+	shutdown, err := Setup(context.Background(), "test", Options{Endpoint: "localhost:4317", Insecure: true, SampleRatio: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = shutdown(ctx)
+}
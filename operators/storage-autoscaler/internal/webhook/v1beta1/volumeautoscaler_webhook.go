@@ -0,0 +1,31 @@
+/*
+Copyright 2026 Volume Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta1 serves the VolumeAutoscaler webhooks.
+package v1beta1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	autoscalingv1beta1 "github.com/volume-autoscaler/volume-autoscaler/api/v1beta1"
+)
+
+// SetupVolumeAutoscalerWebhookWithManager registers the VolumeAutoscaler
+// conversion webhook on /convert. v1beta1 is the hub; every other version in
+// the manager's scheme converts through it.
+func SetupVolumeAutoscalerWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &autoscalingv1beta1.VolumeAutoscaler{}).Complete()
+}
@@ -385,6 +385,10 @@ clusterNamespace: fleet-default" \
   # the rke.cattle.io/rke-machine-pool-name label may not be set early enough for
   # the RKE2 system-agent to include workload-type in kubelet --node-labels.
   log_step "Deploying Node Labeler operator..."
+  # The NodeLabelRule CRD must be established before the rules in the
+  # kustomization can be applied
+  kubectl apply -f "${SERVICES_DIR}/node-labeler/crd.yaml"
+  kubectl wait --for=condition=established --timeout=60s crd/nodelabelrules.labeling.node-labeler.io
   kube_apply_k_subst "${SERVICES_DIR}/node-labeler"
   if ! wait_for_deployment node-labeler node-labeler 120s 2>/dev/null; then
     log_warn "Node Labeler not ready (image may not be built yet) — continuing"